@@ -0,0 +1,169 @@
+package adminplugin
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lampjaw/discordgobot"
+)
+
+// AdminPlugin provides owner only commands for managing a running bot through private messages
+type AdminPlugin struct {
+	discordgobot.Plugin
+}
+
+// New creates a new AdminPlugin
+func New() discordgobot.IPlugin {
+	return &AdminPlugin{}
+}
+
+// Name returns the name of the plugin
+func (p *AdminPlugin) Name() string {
+	return "Admin"
+}
+
+// Commands returns the owner admin commands
+func (p *AdminPlugin) Commands() []*discordgobot.CommandDefinition {
+	return []*discordgobot.CommandDefinition{
+		p.adminCommand("admin-plugins", "plugins", nil, "Lists plugins and their status", p.runPlugins),
+		p.adminCommand("admin-reload", "reload", []discordgobot.CommandDefinitionArgument{
+			{
+				Pattern: ".+",
+				Alias:   "plugin",
+			},
+		}, "Reloads a plugin from storage", p.runReload),
+		p.adminCommand("admin-save", "save", nil, "Saves every plugin", p.runSave),
+		p.adminCommand("admin-uptime", "uptime", nil, "Shows how long the bot has been running", p.runUptime),
+		p.adminCommand("admin-shards", "shards", nil, "Shows shard information", p.runShards),
+		p.adminCommand("admin-stats", "stats", nil, "Shows goroutine, memory and command counts", p.runStats),
+		p.adminCommand("admin-presence", "presence", []discordgobot.CommandDefinitionArgument{
+			{
+				Pattern:  ".+",
+				Alias:    "game",
+				Optional: true,
+			},
+		}, "Changes the game the bot is playing. Clears it when empty", p.runPresence),
+	}
+}
+
+// Help lists the admin commands to the bot owner in private messages
+func (p *AdminPlugin) Help(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, message discordgobot.Message, detailed bool) []string {
+	if !client.IsBotOwner(message) || !client.IsPrivate(message) {
+		return []string{}
+	}
+
+	help := []string{}
+
+	for _, command := range p.Commands() {
+		arguments := make([]string, len(command.Arguments)-1)
+		for i, argument := range command.Arguments[1:] {
+			arguments[i] = argument.Alias
+		}
+
+		help = append(help, discordgobot.CommandHelp(client, "admin "+command.Arguments[0].Pattern, arguments, command.Description, bot.GetCommandPrefix(message)))
+	}
+
+	return help
+}
+
+// adminCommand creates a private owner command triggered by `admin <action>`
+func (p *AdminPlugin) adminCommand(commandID string, action string, arguments []discordgobot.CommandDefinitionArgument, description string, callback func(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload)) *discordgobot.CommandDefinition {
+	return &discordgobot.CommandDefinition{
+		CommandID:   commandID,
+		Description: description,
+		Triggers: []string{
+			"admin",
+		},
+		Arguments: append([]discordgobot.CommandDefinitionArgument{
+			{
+				Pattern: action,
+				Alias:   "action",
+			},
+		}, arguments...),
+		PermissionLevel: discordgobot.PERMISSION_OWNER,
+		ExposureLevel:   discordgobot.EXPOSURE_PRIVATE,
+		Unlisted:        true,
+		Callback:        callback,
+	}
+}
+
+func (p *AdminPlugin) runPlugins(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	client.SendMessage(payload.Message.Channel(), discordgobot.FormatPluginStatuses(bot.PluginStatuses()))
+}
+
+func (p *AdminPlugin) runReload(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	name := strings.TrimSpace(payload.Arguments["plugin"])
+
+	if err := bot.ReloadPlugin(name); err != nil {
+		client.SendMessage(payload.Message.Channel(), err.Error())
+		return
+	}
+
+	client.SendMessage(payload.Message.Channel(), fmt.Sprintf("Reloaded `%s`.", name))
+}
+
+func (p *AdminPlugin) runSave(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	if err := bot.Save(); err != nil {
+		client.SendMessage(payload.Message.Channel(), err.Error())
+		return
+	}
+
+	client.SendMessage(payload.Message.Channel(), "Saved every plugin.")
+}
+
+func (p *AdminPlugin) runUptime(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	client.SendMessage(payload.Message.Channel(), fmt.Sprintf("Uptime: %s", bot.Uptime().Truncate(time.Second)))
+}
+
+func (p *AdminPlugin) runShards(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	lines := []string{}
+
+	for _, session := range client.Sessions {
+		lines = append(lines, fmt.Sprintf("Shard %d/%d - %d guilds, %s heartbeat latency",
+			session.ShardID, session.ShardCount, len(session.State.Guilds), session.HeartbeatLatency().Truncate(time.Millisecond)))
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "No shards connected")
+	}
+
+	client.SendMessage(payload.Message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *AdminPlugin) runStats(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	commandCount := len(bot.RegisteredCommands())
+	for _, plugin := range bot.Plugins {
+		commandCount += len(plugin.Commands())
+	}
+
+	lines := []string{
+		fmt.Sprintf("Uptime: %s", bot.Uptime().Truncate(time.Second)),
+		fmt.Sprintf("Guilds: %d", client.ChannelCount()),
+		fmt.Sprintf("Users: %d", client.UserCount()),
+		fmt.Sprintf("Plugins: %d", len(bot.Plugins)),
+		fmt.Sprintf("Commands: %d", commandCount),
+		fmt.Sprintf("Goroutines: %d", runtime.NumGoroutine()),
+		fmt.Sprintf("Memory: %.2f MB allocated, %.2f MB from system", float64(memStats.Alloc)/1024/1024, float64(memStats.Sys)/1024/1024),
+		fmt.Sprintf("Version: %s", discordgobot.VERSION),
+	}
+
+	client.SendMessage(payload.Message.Channel(), strings.Join(lines, "\n"))
+}
+
+func (p *AdminPlugin) runPresence(bot *discordgobot.Gobot, client *discordgobot.DiscordClient, payload discordgobot.CommandPayload) {
+	game := strings.TrimSpace(payload.Arguments["game"])
+
+	for _, session := range client.Sessions {
+		if err := session.UpdateStatus(0, game); err != nil {
+			client.SendMessage(payload.Message.Channel(), fmt.Sprintf("Error updating presence on shard %d: %v", session.ShardID, err))
+			return
+		}
+	}
+
+	client.SendMessage(payload.Message.Channel(), "Presence updated.")
+}
@@ -0,0 +1,468 @@
+package discordgobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AUDIT_NAMESPACE_PREFIX is prefixed to the guild ID to form the Storage namespace of its audit entries
+const AUDIT_NAMESPACE_PREFIX = "gobot-audit-"
+
+// Defaults for how many audit entries are kept per guild
+const (
+	DEFAULT_AUDIT_MAX_AGE     = 90 * 24 * time.Hour
+	DEFAULT_AUDIT_MAX_ENTRIES = 10000
+)
+
+// AUDIT_PRUNE_INTERVAL is how often the bot prunes the audit log
+const AUDIT_PRUNE_INTERVAL = time.Hour
+
+const (
+	auditSettingsNamespace = "gobot-audit"
+	auditCommandID         = "gobot-cmd-audit"
+	auditDefaultLimit      = 10
+)
+
+// AuditEntry records a single use of a privileged command
+type AuditEntry struct {
+	Time      time.Time         `json:"time"`
+	GuildID   string            `json:"guildId"`
+	ChannelID string            `json:"channelId"`
+	UserID    string            `json:"userId"`
+	UserName  string            `json:"userName"`
+	CommandID string            `json:"commandId"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+	Granted   bool              `json:"granted"`
+	Denial    CommandDenial     `json:"denial,omitempty"`
+}
+
+// AuditQuery filters audit entries. GuildID is required, every other field is optional.
+type AuditQuery struct {
+	GuildID   string
+	UserID    string
+	CommandID string
+	Since     time.Time
+	Until     time.Time
+	// Limit is the maximum number of entries returned, newest first. Every match is returned when 0.
+	Limit int
+}
+
+// AuditLog stores privileged command usage per guild and optionally posts it to a guild channel
+type AuditLog struct {
+	sync.Mutex
+	Storage Storage
+	// MaxAge is how long entries are kept. Entries are kept forever when 0.
+	MaxAge time.Duration
+	// MaxEntries is the number of entries kept per guild. Every entry is kept when 0.
+	MaxEntries int
+
+	sequence uint64
+	// recorded holds the guilds with entries recorded since the last Prune
+	recorded map[string]bool
+}
+
+// NewAuditLog creates an AuditLog that keeps entries in storage for DEFAULT_AUDIT_MAX_AGE up to DEFAULT_AUDIT_MAX_ENTRIES per guild
+func NewAuditLog(storage Storage) *AuditLog {
+	return &AuditLog{
+		Storage:    storage,
+		MaxAge:     DEFAULT_AUDIT_MAX_AGE,
+		MaxEntries: DEFAULT_AUDIT_MAX_ENTRIES,
+	}
+}
+
+// Record stores an audit entry. Old entries are removed by Prune.
+func (a *AuditLog) Record(entry AuditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.Lock()
+	a.sequence++
+	key := fmt.Sprintf("%020d-%06d", entry.Time.UnixNano(), a.sequence%1000000)
+	a.Unlock()
+
+	if err := a.Storage.Put(auditNamespace(entry.GuildID), key, b); err != nil {
+		return err
+	}
+
+	a.Lock()
+	if a.recorded == nil {
+		a.recorded = make(map[string]bool)
+	}
+	a.recorded[entry.GuildID] = true
+	a.Unlock()
+
+	return nil
+}
+
+// Prune deletes the entries older than MaxAge or beyond MaxEntries of every guild with entries recorded since the
+// last Prune. The bot calls it every AUDIT_PRUNE_INTERVAL and when it closes.
+func (a *AuditLog) Prune() error {
+	a.Lock()
+	recorded := a.recorded
+	a.recorded = nil
+	a.Unlock()
+
+	var pruneErr error
+
+	for guildID := range recorded {
+		if err := a.prune(auditNamespace(guildID)); err != nil {
+			pruneErr = err
+
+			a.Lock()
+			if a.recorded == nil {
+				a.recorded = make(map[string]bool)
+			}
+			a.recorded[guildID] = true
+			a.Unlock()
+		}
+	}
+
+	return pruneErr
+}
+
+// prune deletes the entries of a guild that are older than MaxAge or beyond MaxEntries in one batch.
+// Keys start with the entry time so sorting them orders entries oldest first.
+func (a *AuditLog) prune(namespace string) error {
+	if a.MaxAge <= 0 && a.MaxEntries <= 0 {
+		return nil
+	}
+
+	keys, err := a.Storage.List(namespace)
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(keys)
+
+	expired := 0
+
+	if a.MaxEntries > 0 && len(keys) > a.MaxEntries {
+		expired = len(keys) - a.MaxEntries
+	}
+
+	if a.MaxAge > 0 {
+		cutoff := fmt.Sprintf("%020d", time.Now().Add(-a.MaxAge).UnixNano())
+		for expired < len(keys) && keys[expired] < cutoff {
+			expired++
+		}
+	}
+
+	return deleteKeys(a.Storage, namespace, keys[:expired])
+}
+
+// Query returns the audit entries of a guild that match a query, newest first
+func (a *AuditLog) Query(query AuditQuery) ([]AuditEntry, error) {
+	if query.GuildID == "" {
+		return nil, fmt.Errorf("An audit query requires a GuildID")
+	}
+
+	values, err := readNamespace(a.Storage, auditNamespace(query.GuildID))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	entries := []AuditEntry{}
+
+	for _, key := range keys {
+		var entry AuditEntry
+		if err := json.Unmarshal(values[key], &entry); err != nil {
+			return nil, err
+		}
+
+		if !query.matches(entry) {
+			continue
+		}
+
+		entries = append(entries, entry)
+
+		if query.Limit > 0 && len(entries) >= query.Limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// SetLogChannel sets the channel audit entries of a guild are posted to. An empty channelID stops posting.
+// Entries are only posted when the channel belongs to the guild.
+func (a *AuditLog) SetLogChannel(guildID string, channelID string) error {
+	if channelID == "" {
+		return a.Storage.Delete(auditSettingsNamespace, guildID)
+	}
+
+	b, err := json.Marshal(channelID)
+	if err != nil {
+		return err
+	}
+
+	return a.Storage.Put(auditSettingsNamespace, guildID, b)
+}
+
+// LogChannel returns the channel audit entries of a guild are posted to or an empty string if none is set
+func (a *AuditLog) LogChannel(guildID string) (string, error) {
+	b, err := a.Storage.Get(auditSettingsNamespace, guildID)
+	if err != nil || b == nil {
+		return "", err
+	}
+
+	var channelID string
+	err = json.Unmarshal(b, &channelID)
+
+	return channelID, err
+}
+
+func (q AuditQuery) matches(entry AuditEntry) bool {
+	if q.UserID != "" && entry.UserID != q.UserID {
+		return false
+	}
+
+	if q.CommandID != "" && entry.CommandID != q.CommandID {
+		return false
+	}
+
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+
+	if !q.Until.IsZero() && entry.Time.After(q.Until) {
+		return false
+	}
+
+	return true
+}
+
+// String formats an entry as a single line for posting to discord
+func (e AuditEntry) String() string {
+	result := "granted"
+	if !e.Granted {
+		result = fmt.Sprintf("denied (%s)", e.Denial)
+	}
+
+	arguments := make([]string, 0, len(e.Arguments))
+	for alias, value := range e.Arguments {
+		arguments = append(arguments, fmt.Sprintf("%s=%s", alias, value))
+	}
+	sort.Strings(arguments)
+
+	line := fmt.Sprintf("%s `%s` (%s) used `%s` in <#%s>: %s", e.Time.UTC().Format(time.RFC3339), e.UserName, e.UserID, e.CommandID, e.ChannelID, result)
+
+	if len(arguments) > 0 {
+		line += fmt.Sprintf(" `%s`", strings.Join(arguments, " "))
+	}
+
+	return line
+}
+
+func auditNamespace(guildID string) string {
+	return AUDIT_NAMESPACE_PREFIX + guildID
+}
+
+// isAudited determines if a command should be audited for a message
+func (b *Gobot) isAudited(commandDefinition *CommandDefinition, message Message) bool {
+	if b.Audit == nil {
+		return false
+	}
+
+	if commandDefinition.Audited {
+		return true
+	}
+
+	permissionLevel := commandDefinition.PermissionLevel
+	if override := b.getPermissionOverride(commandDefinition, message); override != nil && override.PermissionLevel > 0 {
+		permissionLevel = override.PermissionLevel
+	}
+
+	return permissionLevel > 0 && permissionLevel <= PERMISSION_MODERATOR
+}
+
+// audit records a command use and posts it to the guild's audit channel. Storage and discord are written to in the
+// background so the command isn't delayed.
+func (b *Gobot) audit(commandDefinition *CommandDefinition, message Message, arguments map[string]string, denial CommandDenial) {
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return
+	}
+
+	entry := AuditEntry{
+		Time:      time.Now(),
+		GuildID:   guildID,
+		ChannelID: message.Channel(),
+		UserID:    message.UserID(),
+		UserName:  message.UserName(),
+		CommandID: commandDefinition.CommandID,
+		Granted:   denial == "",
+		Denial:    denial,
+	}
+
+	if len(arguments) > 0 {
+		entry.Arguments = make(map[string]string, len(arguments))
+		for alias, value := range arguments {
+			entry.Arguments[alias] = value
+		}
+	}
+
+	go b.recordAudit(entry, b.messageLogFields(message))
+}
+
+func (b *Gobot) startAuditPrune() {
+	if b.Audit == nil || b.stopAuditPrune != nil {
+		return
+	}
+
+	b.stopAuditPrune = make(chan bool)
+
+	go func(stop chan bool) {
+		ticker := time.NewTicker(AUDIT_PRUNE_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				b.pruneAudit()
+			case <-stop:
+				return
+			}
+		}
+	}(b.stopAuditPrune)
+}
+
+func (b *Gobot) pruneAudit() {
+	if err := b.Audit.Prune(); err != nil {
+		b.logError("Error pruning audit log", err)
+	}
+}
+
+func (b *Gobot) recordAudit(entry AuditEntry, fields []LogField) {
+	if err := b.Audit.Record(entry); err != nil {
+		b.logError("Error recording audit entry", err, append(fields, Field(LOG_FIELD_COMMAND, entry.CommandID))...)
+	}
+
+	channelID, err := b.Audit.LogChannel(entry.GuildID)
+	if err != nil {
+		b.logError("Error reading audit channel", err, Field(LOG_FIELD_GUILD, entry.GuildID))
+		return
+	}
+
+	if channelID == "" {
+		return
+	}
+
+	if !b.isGuildChannel(entry.GuildID, channelID) {
+		b.logWarn("Audit channel isn't in the guild", Field(LOG_FIELD_GUILD, entry.GuildID), Field(LOG_FIELD_CHANNEL, channelID))
+		return
+	}
+
+	b.Client.SendMessage(channelID, entry.String())
+}
+
+// isGuildChannel determines if a channel belongs to a guild
+func (b *Gobot) isGuildChannel(guildID string, channelID string) bool {
+	channel, err := b.Client.Channel(channelID)
+
+	return err == nil && channel != nil && channel.GuildID == guildID
+}
+
+func (b *Gobot) registerAuditCommands() {
+	b.registerBuiltinCommand(&CommandDefinition{
+		CommandID:   auditCommandID,
+		Description: "Shows recent privileged command use or sets the audit log channel. Actions: recent, channel",
+		Triggers: []string{
+			"audit",
+		},
+		Arguments: []CommandDefinitionArgument{
+			{
+				Pattern: "recent|channel",
+				Alias:   "action",
+			},
+			{
+				Pattern:  "off|<#[0-9]+>|<@!?[0-9]+>|[0-9]+",
+				Alias:    "target",
+				Optional: true,
+			},
+		},
+		PermissionLevel: PERMISSION_ADMIN,
+		ExposureLevel:   EXPOSURE_PUBLIC,
+		Callback:        handleAuditCommand,
+	})
+}
+
+func handleAuditCommand(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	channelID := payload.Message.Channel()
+
+	guildID, err := payload.Message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		client.SendMessage(channelID, "The audit log is only available in a server.")
+		return
+	}
+
+	target := payload.Arguments["target"]
+
+	switch payload.Arguments["action"] {
+	case "channel":
+		logChannelID := ""
+		if m := channelMentionRegex.FindStringSubmatch(target); m != nil {
+			logChannelID = m[1]
+		} else if target != "off" {
+			client.SendMessage(channelID, "Use a #channel mention or `off`.")
+			return
+		}
+
+		if logChannelID != "" && !bot.isGuildChannel(guildID, logChannelID) {
+			client.SendMessage(channelID, "The audit channel must be in this server.")
+			return
+		}
+
+		if err := bot.Audit.SetLogChannel(guildID, logChannelID); err != nil {
+			client.SendMessage(channelID, fmt.Sprintf("Unable to set the audit channel: %v", err))
+			return
+		}
+
+		if logChannelID == "" {
+			client.SendMessage(channelID, "Audit entries will no longer be posted.")
+		} else {
+			client.SendMessage(channelID, fmt.Sprintf("Audit entries will be posted to <#%s>.", logChannelID))
+		}
+	case "recent":
+		query := AuditQuery{
+			GuildID: guildID,
+			Limit:   auditDefaultLimit,
+		}
+
+		if m := userMentionRegex.FindStringSubmatch(target); m != nil {
+			query.UserID = m[1]
+		} else if target != "" && target != "off" {
+			query.UserID = target
+		}
+
+		entries, err := bot.Audit.Query(query)
+		if err != nil {
+			client.SendMessage(channelID, fmt.Sprintf("Unable to read the audit log: %v", err))
+			return
+		}
+
+		if len(entries) == 0 {
+			client.SendMessage(channelID, "No audit entries found.")
+			return
+		}
+
+		lines := make([]string, len(entries))
+		for i, entry := range entries {
+			lines[i] = entry.String()
+		}
+
+		client.SendMessage(channelID, strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,99 @@
+package discordgobot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IDirtyPlugin is implemented by plugins that can report unsaved changes. Plugins that aren't dirty are skipped by Save.
+type IDirtyPlugin interface {
+	// IsDirty returns true when the plugin has changes that haven't been saved
+	IsDirty() bool
+}
+
+// SaveError holds the errors returned by plugins during Save by plugin name
+type SaveError map[string]error
+
+func (e SaveError) Error() string {
+	messages := make([]string, 0, len(e))
+
+	for name, err := range e {
+		messages = append(messages, fmt.Sprintf("%s: %v", name, err))
+	}
+	sort.Strings(messages)
+
+	return fmt.Sprintf("Error saving plugins: %s", strings.Join(messages, "; "))
+}
+
+type readLocker interface {
+	RLock()
+	RUnlock()
+}
+
+func (b *Gobot) startAutoSave() {
+	if b.Config == nil || b.Config.AutoSaveInterval <= 0 || b.stopAutoSave != nil {
+		return
+	}
+
+	b.stopAutoSave = make(chan bool)
+
+	go func(interval time.Duration, stop chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				b.Save()
+			case <-stop:
+				return
+			}
+		}
+	}(b.Config.AutoSaveInterval, b.stopAutoSave)
+}
+
+// savePlugin saves a plugin unless it reports it isn't dirty. IsDirty is checked while holding the plugin's read lock
+// but the lock is released before Save so plugins can take their own locks while saving.
+func savePlugin(plugin IPlugin) error {
+	if dirtyPlugin, ok := plugin.(IDirtyPlugin); ok && !isPluginDirty(plugin, dirtyPlugin) {
+		return nil
+	}
+
+	return plugin.Save()
+}
+
+func isPluginDirty(plugin IPlugin, dirtyPlugin IDirtyPlugin) bool {
+	if locker, ok := plugin.(readLocker); ok {
+		locker.RLock()
+		defer locker.RUnlock()
+	}
+
+	return dirtyPlugin.IsDirty()
+}
+
+// savePlugins saves every plugin. Saves are serialised so an autosave and a manual save never run Save at the same time.
+func (b *Gobot) savePlugins() error {
+	b.saveMutex.Lock()
+	defer b.saveMutex.Unlock()
+
+	errors := SaveError{}
+
+	for _, plugin := range b.Plugins {
+		if b.isPluginFailed(plugin) {
+			continue
+		}
+
+		if err := savePlugin(plugin); err != nil {
+			b.logError("Error saving plugin", err, Field(LOG_FIELD_PLUGIN, plugin.Name()))
+			errors[plugin.Name()] = err
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package discordgobot
+
+import (
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStorage is a Storage backed by an embedded bbolt database. Each namespace is a bucket.
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage opens or creates a bbolt database at fileName
+func NewBoltStorage(fileName string) (*BoltStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(fileName, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltStorage{
+		db: db,
+	}, nil
+}
+
+// Close closes the database
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value of a key or nil if it doesn't exist
+func (s *BoltStorage) Get(namespace string, key string) ([]byte, error) {
+	var value []byte
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		if v := bucket.Get([]byte(key)); v != nil {
+			value = append([]byte(nil), v...)
+		}
+
+		return nil
+	})
+
+	return value, err
+}
+
+// Put stores the value of a key
+func (s *BoltStorage) Put(namespace string, key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(namespace))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(key), value)
+	})
+}
+
+// Delete removes a key
+func (s *BoltStorage) Delete(namespace string, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete([]byte(key))
+	})
+}
+
+// DeleteAll removes keys from a namespace in a single transaction
+func (s *BoltStorage) DeleteAll(namespace string, keys []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		for _, key := range keys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// List returns every key in a namespace
+func (s *BoltStorage) List(namespace string) ([]string, error) {
+	keys := []string{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+
+	return keys, err
+}
+
+// GetAll returns every key and value in a namespace
+func (s *BoltStorage) GetAll(namespace string) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			values[string(k)] = append([]byte(nil), v...)
+			return nil
+		})
+	})
+
+	return values, err
+}
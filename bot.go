@@ -1,13 +1,15 @@
 package discordgobot
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"net/http"
 	"os"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lampjaw/discordclient"
 )
@@ -27,16 +29,116 @@ type GobotConf struct {
 	OwnerUserID string
 	// CommandLookupDisabled allows for the ?commands command to be disabled
 	CommandLookupDisabled bool
+	// CommandsPageSize is the number of commands shown per page when ?commands is called. Pagination is disabled when 0.
+	CommandsPageSize int
+	// CommandsPageTimeout is how long the ?commands pages can be navigated. Defaults to DEFAULT_PAGINATOR_TIMEOUT.
+	CommandsPageTimeout time.Duration
+	// BuiltinCommandsEnabled registers the ?perm, ?enable, ?disable, ?prefix and ?plugins commands. Each can still be
+	// turned off with its own Disabled option.
+	BuiltinCommandsEnabled bool
+	// PermissionStore holds per guild command permission overrides. Defaults to a MemoryPermissionStore.
+	PermissionStore PermissionStore
+	// PermissionCommandsDisabled allows for the ?perm command to be disabled
+	PermissionCommandsDisabled bool
+	// EnablementStore holds which plugins and commands are enabled per guild and channel. Defaults to a MemoryEnablementStore.
+	EnablementStore EnablementStore
+	// EnablementCommandsDisabled allows for the ?enable and ?disable commands to be disabled
+	EnablementCommandsDisabled bool
+	// PrefixStore holds per guild command prefixes. Defaults to a MemoryPrefixStore.
+	PrefixStore PrefixStore
+	// PrefixCommandsDisabled allows for the ?prefix command to be disabled
+	PrefixCommandsDisabled bool
+	// Storage persists plugin state, audit entries and scheduled jobs. Defaults to a FileStorage in StorageDirectory.
+	Storage Storage
+	// StorageDirectory is the directory of the default FileStorage. Nothing is written to disk when it's empty and
+	// Storage isn't set, a MemoryStorage is used instead.
+	StorageDirectory string
+	// AutoSaveInterval periodically calls Save while the bot is open. Disabled when 0.
+	AutoSaveInterval time.Duration
+	// PluginFailurePolicy determines if a plugin that fails to Init or Load stops Open or is quarantined. Defaults to PLUGIN_FAILURE_QUARANTINE.
+	PluginFailurePolicy PluginFailurePolicy
+	// PluginStatusCommandDisabled allows for the owner ?plugins command to be disabled
+	PluginStatusCommandDisabled bool
+	// Logger receives structured log entries from the bot. Defaults to a StdLogger at LOG_INFO.
+	Logger Logger
+	// MessageContentLoggingDisabled prevents the content of messages from being logged
+	MessageContentLoggingDisabled bool
+	// MetricsEnabled records message and command metrics in Gobot.Metrics
+	MetricsEnabled bool
+	// Tracer creates spans around message dispatch and command callbacks. Tracing is disabled when nil.
+	Tracer Tracer
+	// MetricsAddress serves metrics over http on this address, e.g. "localhost:9100". Enables metrics when set.
+	MetricsAddress string
+	// AuditEnabled records privileged command usage in Gobot.Audit using Storage
+	AuditEnabled bool
+	// AuditCommandsDisabled allows for the ?audit command to be disabled
+	AuditCommandsDisabled bool
+	// AuditMaxAge is how long audit entries are kept. Defaults to DEFAULT_AUDIT_MAX_AGE.
+	AuditMaxAge time.Duration
+	// AuditMaxEntries is the number of audit entries kept per guild. Defaults to DEFAULT_AUDIT_MAX_ENTRIES.
+	AuditMaxEntries int
+	// CancelWords end a conversation started with AwaitMessage. Defaults to DEFAULT_CANCEL_WORDS.
+	CancelWords []string
+	// CommandEditsDisabled stops commands from running again when the message that called them is edited
+	CommandEditsDisabled bool
+	// EditRepliesEnabled edits the replies sent with Reply when a command runs again because its message was edited instead of sending new ones
+	EditRepliesEnabled bool
+	// ReplyTrackingTTL is how long the replies to a command are remembered. Defaults to DEFAULT_REPLY_TRACKING_TTL.
+	ReplyTrackingTTL time.Duration
+	// InteractionPublicKey is the hex encoded public key of the application used to verify interaction requests
+	InteractionPublicKey string
+	// InteractionsAddress serves the interactions endpoint over http on this address, e.g. "localhost:8080"
+	InteractionsAddress string
+	// ConfirmTimeout is how long commands with Confirm set wait for an answer. Defaults to DEFAULT_CONFIRM_TIMEOUT.
+	ConfirmTimeout time.Duration
+	// CommandFile is a YAML or JSON file of response commands loaded with LoadCommandFile when the bot is opened
+	CommandFile string
+	// CommandFileReloadInterval checks CommandFile for changes and reloads it at this interval. Reloading is disabled when zero.
+	CommandFileReloadInterval time.Duration
 }
 
-// Gobot handles bot related functionality
+// Gobot handles bot related functionality.
+// Commands may change while the bot is running, e.g. when a CommandFile is reloaded, so use RegisteredCommands
+// and FindCommandDefinition instead of reading the Commands map directly.
 type Gobot struct {
 	Client          *DiscordClient
 	Plugins         map[string]IPlugin
 	Commands        map[string]*CommandDefinition
 	Config          *GobotConf
+	Permissions     PermissionStore
+	Enablement      EnablementStore
+	Prefixes        PrefixStore
+	Storage         Storage
+	Metrics         *Metrics
+	Audit           *AuditLog
 	messageChannels []chan Message
 	State           interface{}
+
+	commandMutex       sync.RWMutex
+	builtinCommands    map[string]*CommandDefinition
+	reactionListeners  map[string]ReactionListener
+	reactionMutex      sync.RWMutex
+	stopAutoSave       chan bool
+	stopAuditPrune     chan bool
+	saveMutex          sync.Mutex
+	commandFiles       commandFiles
+	stopFileWatch      chan bool
+	migrations         map[string]map[int]MigrationFunc
+	migrationMutex     sync.RWMutex
+	services           []interface{}
+	serviceMutex       sync.RWMutex
+	pluginOrder        []IPlugin
+	readyShards        map[int]bool
+	readyMutex         sync.Mutex
+	readyOnce          sync.Once
+	pluginStatuses     map[string]PluginStatus
+	pluginStatusMutex  sync.RWMutex
+	openTime           time.Time
+	metricsServer      *http.Server
+	interactionsServer *http.Server
+	conversations      messageWaiters
+	replies            replyTracker
+	scheduler          scheduler
 }
 
 // Open starts listening for discord messages with a recommended number of shards
@@ -56,19 +158,42 @@ func (b *Gobot) OpenShard(shardCount int, shardID int) error {
 
 func (b *Gobot) openShardsInternal(shardCount int, shardID int) error {
 	for _, plugin := range b.Plugins {
-		if !validatePlugin(plugin) {
+		if !validatePlugin(b.Logger(), plugin) {
 			return fmt.Errorf("A misconfigured plugin was found: '%s'", plugin.Name())
 		}
 	}
 
-	for _, command := range b.Commands {
-		if !validateCommand(command) {
+	if b.Config != nil && b.Config.CommandFile != "" {
+		if err := b.LoadCommandFile(b.Config.CommandFile); err != nil {
+			return err
+		}
+	}
+
+	b.removeShadowedBuiltinCommands()
+
+	for _, command := range b.RegisteredCommands() {
+		if !validateCommand(b.Logger(), command) {
 			return fmt.Errorf("A misconfigured command was found: '%s'", command.CommandID)
 		}
 	}
 
+	pluginOrder, err := sortPlugins(b.Plugins)
+	if err != nil {
+		return err
+	}
+	b.pluginOrder = pluginOrder
+
+	for _, plugin := range b.pluginOrder {
+		if err := b.resolvePluginServices(plugin); err != nil {
+			return err
+		}
+	}
+
+	if err := b.initPlugins(); err != nil {
+		return err
+	}
+
 	var messageChan <-chan discordclient.Message
-	var err error
 
 	if shardCount < 1 {
 		messageChan, err = b.Client.Listen(-1)
@@ -82,26 +207,52 @@ func (b *Gobot) openShardsInternal(shardCount int, shardID int) error {
 		return fmt.Errorf("Error creating discord service: %v", err)
 	}
 
-	for _, plugin := range b.Plugins {
-		plugin.Load(b.Client)
+	b.openTime = time.Now()
+
+	if err := b.loadPlugins(); err != nil {
+		for _, session := range b.Client.Sessions {
+			session.Close()
+		}
+		return err
 	}
 
+	// Event handlers are registered once plugins have loaded so no plugin receives events before Load
+	b.registerReactionHandlers()
+	b.registerEventHandlers()
+
+	b.startAutoSave()
+	b.startAuditPrune()
+	b.startCommandFileWatch()
+	b.startMetricsServer()
+	b.startInteractionsServer()
+	b.startScheduler()
+
 	go b.listen(messageChan)
 
+	b.registerReadyHandlers()
+
 	return nil
 }
 
-// Save writes all plugin data to disk
-func (b *Gobot) Save() {
-	for _, plugin := range b.Plugins {
-		plugin.Save()
+// Uptime returns how long the bot has been open
+func (b *Gobot) Uptime() time.Duration {
+	if b.openTime.IsZero() {
+		return 0
 	}
+
+	return time.Since(b.openTime)
+}
+
+// Save writes all plugin data to disk. Plugins that report they aren't dirty are skipped. A SaveError is returned
+// if any plugin fails to save.
+func (b *Gobot) Save() error {
+	return b.savePlugins()
 }
 
 // RegisterPlugin registers a plugin to process messages or commands
 func (b *Gobot) RegisterPlugin(plugin IPlugin) {
 	if b.Plugins[plugin.Name()] != nil {
-		log.Println("Plugin with that name already registered", plugin.Name())
+		b.logWarn("Plugin with that name already registered", Field(LOG_FIELD_PLUGIN, plugin.Name()))
 	}
 	b.Plugins[plugin.Name()] = plugin
 }
@@ -122,93 +273,265 @@ func (b *Gobot) RegisterPrefixCommand(prefix string, trigger string, description
 		CommandPrefix: prefix,
 		Callback:      callback,
 	}
-	b.Commands[def.CommandID] = def
+	b.UpdateCommandDefinition(def)
 }
 
 // RegisterCommandDefinition registers a command definition
 func (b *Gobot) RegisterCommandDefinition(cmdDef *CommandDefinition) {
+	b.commandMutex.Lock()
+	defer b.commandMutex.Unlock()
+
 	if b.Commands[cmdDef.CommandID] != nil {
-		log.Println("Command with that id is already registered", cmdDef.CommandID)
+		b.logWarn("Command with that id is already registered", Field(LOG_FIELD_COMMAND, cmdDef.CommandID))
 	}
 	b.Commands[cmdDef.CommandID] = cmdDef
 }
 
+// registerBuiltinCommand registers a command provided by the bot itself
+func (b *Gobot) registerBuiltinCommand(cmdDef *CommandDefinition) {
+	b.commandMutex.Lock()
+	if b.builtinCommands == nil {
+		b.builtinCommands = make(map[string]*CommandDefinition)
+	}
+	b.builtinCommands[cmdDef.CommandID] = cmdDef
+	b.commandMutex.Unlock()
+
+	b.RegisterCommandDefinition(cmdDef)
+}
+
+// removeShadowedBuiltinCommands removes the built in commands that share a trigger with a registered or plugin command
+// so a bot's own commands keep working when the bot adds a command with the same trigger
+func (b *Gobot) removeShadowedBuiltinCommands() {
+	b.commandMutex.RLock()
+	builtinCommands := make(map[string]*CommandDefinition, len(b.builtinCommands))
+	for commandID, command := range b.builtinCommands {
+		if b.Commands[commandID] == command {
+			builtinCommands[commandID] = command
+		}
+	}
+	b.commandMutex.RUnlock()
+
+	triggers := make(map[string]string)
+
+	for _, command := range b.RegisteredCommands() {
+		if builtinCommands[command.CommandID] == command {
+			continue
+		}
+
+		for _, trigger := range command.Triggers {
+			triggers[trigger] = command.CommandID
+		}
+	}
+
+	for _, plugin := range b.Plugins {
+		for _, command := range plugin.Commands() {
+			for _, trigger := range command.Triggers {
+				triggers[trigger] = command.CommandID
+			}
+		}
+	}
+
+	for commandID, command := range builtinCommands {
+		for _, trigger := range command.Triggers {
+			if usedBy, ok := triggers[trigger]; ok {
+				b.logWarn("Built in command skipped because its trigger is already used", Field(LOG_FIELD_COMMAND, commandID), Field("trigger", trigger), Field("usedBy", usedBy))
+				b.RemoveCommand(commandID)
+				break
+			}
+		}
+	}
+}
+
 // RemoveCommand unregisters a command. Does not effect plugins.
 func (b *Gobot) RemoveCommand(commandID string) {
+	b.commandMutex.Lock()
+	defer b.commandMutex.Unlock()
+
 	delete(b.Commands, commandID)
 }
 
 // UpdateCommandDefinition updates a command definition or registers if it doesn't exist
 func (b *Gobot) UpdateCommandDefinition(cmdDef *CommandDefinition) {
+	b.commandMutex.Lock()
+	defer b.commandMutex.Unlock()
+
 	b.Commands[cmdDef.CommandID] = cmdDef
 }
 
+// FindCommandDefinition finds a registered or plugin command by CommandID or trigger
+func (b *Gobot) FindCommandDefinition(commandIDOrTrigger string) *CommandDefinition {
+	b.commandMutex.RLock()
+	command := b.Commands[commandIDOrTrigger]
+	b.commandMutex.RUnlock()
+
+	if command != nil {
+		return command
+	}
+
+	for _, plugin := range b.Plugins {
+		for _, command := range plugin.Commands() {
+			if command.CommandID == commandIDOrTrigger {
+				return command
+			}
+		}
+	}
+
+	for _, command := range b.RegisteredCommands() {
+		for _, trigger := range command.Triggers {
+			if trigger == commandIDOrTrigger {
+				return command
+			}
+		}
+	}
+
+	for _, plugin := range b.Plugins {
+		for _, command := range plugin.Commands() {
+			for _, trigger := range command.Triggers {
+				if trigger == commandIDOrTrigger {
+					return command
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegisteredCommands returns a snapshot of Commands that's safe to use while commands are registered or removed
+func (b *Gobot) RegisteredCommands() []*CommandDefinition {
+	b.commandMutex.RLock()
+	defer b.commandMutex.RUnlock()
+
+	commands := make([]*CommandDefinition, 0, len(b.Commands))
+	for _, command := range b.Commands {
+		commands = append(commands, command)
+	}
+
+	return commands
+}
+
 // GetCommandPrefix returns the prefix as configured in the GobotConf or the default if none is available
 func (b *Gobot) GetCommandPrefix(message Message) string {
-	if b.Config != nil {
-		if b.Config.CommandPrefixFunc != nil {
-			return b.Config.CommandPrefixFunc(b, b.Client, message)
-		}
+	return b.GetCommandPrefixes(message)[0]
+}
+
+// GetCommandPrefixes returns every prefix that can be used for a message. CommandPrefixFunc is used first, then any
+// prefixes set for the guild, then the CommandPrefix configured in the GobotConf or the default if none is available.
+func (b *Gobot) GetCommandPrefixes(message Message) []string {
+	if b.Config != nil && b.Config.CommandPrefixFunc != nil {
+		return []string{b.Config.CommandPrefixFunc(b, b.Client, message)}
+	}
 
-		if b.Config.CommandPrefix != "" {
-			return b.Config.CommandPrefix
+	if b.Prefixes != nil {
+		if guildID, err := message.ResolveGuildID(); err == nil && guildID != "" {
+			prefixes, err := b.Prefixes.GetPrefixes(guildID)
+			if err != nil {
+				b.logError("Error reading command prefixes", err, Field(LOG_FIELD_GUILD, guildID))
+			} else if len(prefixes) > 0 {
+				return prefixes
+			}
 		}
 	}
 
-	return DEFAULT_COMMAND_PREFIX
+	if b.Config != nil && b.Config.CommandPrefix != "" {
+		return []string{b.Config.CommandPrefix}
+	}
+
+	return []string{DEFAULT_COMMAND_PREFIX}
 }
 
-func (b *Gobot) getData(plugin IPlugin) []byte {
-	fileName := "data/" + plugin.Name()
+func (b *Gobot) matchCommandPrefix(message Message) string {
+	prefixes := b.GetCommandPrefixes(message)
+	matched := ""
 
-	if b, err := ioutil.ReadFile(fileName); err == nil {
-		return b
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(message.RawMessage(), prefix) && len(prefix) > len(matched) {
+			matched = prefix
+		}
 	}
 
-	return nil
+	if matched == "" {
+		return prefixes[0]
+	}
+
+	return matched
 }
 
 func (b *Gobot) listen(messageChan <-chan discordclient.Message) {
-	log.Printf("Listening")
+	b.logInfo("Listening")
 	for {
 		message := <-messageChan
 
-		commandPrefix := b.GetCommandPrefix(message)
+		b.dispatchMessage(message)
+	}
+}
 
-		if isCommandsRequest(b.Client, commandPrefix, message) {
-			go handleCommandsRequest(b, message, commandPrefix)
-			continue
-		}
+func (b *Gobot) dispatchMessage(message Message) {
+	ctx, span := b.StartSpan(context.Background(), SPAN_MESSAGE, b.messageSpanFields(message)...)
+	defer span.End()
 
-		messageParts := strings.Fields(message.RawMessage())
+	if b.Metrics != nil {
+		b.Metrics.messageReceived(b.shardForMessage(message))
+	}
 
-		for _, command := range b.Commands {
-			if !b.Client.IsMe(message) {
-				go findCommandDefinitionCommandMatch(b, command, message, commandPrefix, messageParts)
-			}
+	if b.interceptMessage(message) {
+		span.SetField("intercepted", true)
+		return
+	}
+
+	if message.Type() != discordclient.MessageTypeCreate {
+		b.dispatchMessageEvent(message)
+	}
+
+	if message.Type() == discordclient.MessageTypeDelete {
+		go b.deleteReplies(message)
+	}
+
+	_, prefixSpan := b.StartSpan(ctx, SPAN_PREFIX)
+	commandPrefix := b.matchCommandPrefix(message)
+	prefixSpan.SetField("prefix", commandPrefix)
+	prefixSpan.End()
+
+	processCommands := !b.Client.IsMe(message) && b.isCommandMessage(message)
+
+	if processCommands && isCommandsRequest(b.Client, commandPrefix, message) {
+		go handleCommandsRequest(b, message, commandPrefix)
+		return
+	}
+
+	messageParts := strings.Fields(message.RawMessage())
+
+	for _, command := range b.RegisteredCommands() {
+		if processCommands {
+			go findCommandDefinitionCommandMatch(ctx, b, command, message, commandPrefix, messageParts)
 		}
+	}
 
-		for _, plugin := range b.Plugins {
-			go plugin.Message(b, b.Client, message)
-			if !b.Client.IsMe(message) {
-				go findPluginCommandMatch(b, plugin, message, commandPrefix, messageParts)
-			}
+	for _, plugin := range b.Plugins {
+		if !b.isPluginLoaded(plugin) || !b.IsPluginEnabled(plugin, message) {
+			continue
+		}
+
+		plugin := plugin
+		go b.runPluginHandler(plugin, EVENT_MESSAGE, func() { plugin.Message(b, b.Client, message) })
+		if processCommands {
+			go findPluginCommandMatch(ctx, b, plugin, message, commandPrefix, messageParts)
 		}
 	}
 }
 
-func findPluginCommandMatch(b *Gobot, plugin IPlugin, message Message, commandPrefix string, parts []string) {
-	if plugin.Commands() == nil || message.Message() == "" {
+func findPluginCommandMatch(ctx context.Context, b *Gobot, plugin IPlugin, message Message, commandPrefix string, parts []string) {
+	if plugin.Commands() == nil || message.Message() == "" || !b.isPluginLoaded(plugin) {
 		return
 	}
 
 	for _, commandDefinition := range plugin.Commands() {
-		findCommandDefinitionCommandMatch(b, commandDefinition, message, commandPrefix, parts)
+		findCommandDefinitionCommandMatch(ctx, b, commandDefinition, message, commandPrefix, parts)
 	}
 }
 
-func findCommandDefinitionCommandMatch(b *Gobot, commandDefinition *CommandDefinition, message Message, commandPrefix string, parts []string) {
-	if message.Message() == "" || !validateCommandAccess(b.Client, commandDefinition, message) {
+func findCommandDefinitionCommandMatch(ctx context.Context, b *Gobot, commandDefinition *CommandDefinition, message Message, commandPrefix string, parts []string) {
+	if message.Message() == "" {
 		return
 	}
 
@@ -219,24 +542,132 @@ func findCommandDefinitionCommandMatch(b *Gobot, commandDefinition *CommandDefin
 	}
 
 	for _, trigger := range commandDefinition.Triggers {
-		if isTriggerMatch, triggerMatch := findTriggerMatch(commandDefinition, trigger, definitionPrefix, parts, message); isTriggerMatch {
-			if isArgumentMatch, parsedArgs := extractCommandArguments(message, triggerMatch, commandDefinition.Arguments); isArgumentMatch {
-				log.Printf("<%s> %s: %s\n", message.Channel(), message.UserName(), message.RawMessage())
-
-				payload := CommandPayload{
-					CommandID: commandDefinition.CommandID,
-					Trigger:   trigger,
-					Arguments: parsedArgs,
-					Message:   message,
-				}
+		_, matchSpan := b.StartSpan(ctx, SPAN_MATCH, Field(LOG_FIELD_COMMAND, commandDefinition.CommandID), Field("trigger", trigger))
+		isTriggerMatch, triggerMatch := findTriggerMatch(commandDefinition, trigger, definitionPrefix, parts, message)
+		matchSpan.SetField("matched", isTriggerMatch)
+		matchSpan.End()
 
-				go commandDefinition.Callback(b, b.Client, payload)
-			}
+		if !isTriggerMatch {
+			continue
 		}
+
+		b.Metrics.commandMatched(commandDefinition.CommandID)
+
+		commandCtx, commandSpan := b.StartSpan(ctx, SPAN_COMMAND, Field(LOG_FIELD_COMMAND, commandDefinition.CommandID), Field("trigger", trigger))
+		b.dispatchCommand(commandCtx, commandSpan, commandDefinition, message, trigger, triggerMatch)
+		commandSpan.End()
 	}
 }
 
+// dispatchCommand checks access and arguments then runs the command. Returns the reason the command wasn't run, if any.
+func (b *Gobot) dispatchCommand(ctx context.Context, span Span, commandDefinition *CommandDefinition, message Message, trigger string, triggerMatch string) CommandDenial {
+	if !b.IsCommandEnabled(commandDefinition, message) {
+		return b.denyCommand(span, commandDefinition, DENIED_DISABLED)
+	}
+
+	_, accessSpan := b.StartSpan(ctx, SPAN_ACCESS)
+	denial := checkCommandAccess(b, b.Client, commandDefinition, message)
+	accessSpan.SetField("denied", denial)
+	accessSpan.End()
+
+	audited := b.isAudited(commandDefinition, message)
+
+	if denial != "" {
+		if audited {
+			b.audit(commandDefinition, message, nil, denial)
+		}
+		return b.denyCommand(span, commandDefinition, denial)
+	}
+
+	_, argumentSpan := b.StartSpan(ctx, SPAN_ARGUMENTS)
+	var isArgumentMatch bool
+	var parsedArgs map[string]string
+	if m, ok := message.(*interactionMessage); ok {
+		isArgumentMatch, parsedArgs = m.arguments(commandDefinition.Arguments)
+	} else {
+		isArgumentMatch, parsedArgs = extractCommandArguments(message, triggerMatch, commandDefinition.Arguments)
+	}
+	argumentSpan.SetField("matched", isArgumentMatch)
+	argumentSpan.End()
+
+	if !isArgumentMatch {
+		return b.denyCommand(span, commandDefinition, DENIED_ARGUMENTS)
+	}
+
+	b.logInfo("Command received", append(b.messageContentLogFields(message), Field(LOG_FIELD_COMMAND, commandDefinition.CommandID))...)
+
+	if audited {
+		b.audit(commandDefinition, message, parsedArgs, "")
+	}
+
+	payload := CommandPayload{
+		CommandID: commandDefinition.CommandID,
+		Trigger:   trigger,
+		Arguments: parsedArgs,
+		Message:   message,
+		Context:   ctx,
+		Edited:    message.Type() == discordclient.MessageTypeUpdate,
+
+		reply: &commandReply{
+			deleteWithMessage: commandDefinition.DeleteRepliesWithMessage,
+		},
+	}
+
+	if m, ok := message.(*interactionMessage); ok {
+		payload.interaction = m.interaction
+	}
+
+	go b.runCommand(commandDefinition, payload)
+
+	return ""
+}
+
+// denyCommand records why a command wasn't run on its metrics and span
+func (b *Gobot) denyCommand(span Span, commandDefinition *CommandDefinition, denial CommandDenial) CommandDenial {
+	b.Metrics.commandDenied(commandDefinition.CommandID, denial)
+	span.SetField("denied", denial)
+	span.SetError(fmt.Errorf("Command '%s' denied: %s", commandDefinition.CommandID, denial))
+
+	return denial
+}
+
+func (b *Gobot) runCommand(commandDefinition *CommandDefinition, payload CommandPayload) {
+	if payload.interaction != nil {
+		defer b.finishInteraction(payload)
+	}
+
+	if commandDefinition.Confirm {
+		_, confirmSpan := b.StartSpan(payload.Context, SPAN_CONFIRM)
+		confirmed := b.confirmCommand(commandDefinition, payload)
+		confirmSpan.SetField("confirmed", confirmed)
+		if !confirmed {
+			confirmSpan.SetError(fmt.Errorf("Command '%s' wasn't confirmed", commandDefinition.CommandID))
+		}
+		confirmSpan.End()
+
+		if !confirmed {
+			b.Metrics.commandDenied(commandDefinition.CommandID, DENIED_UNCONFIRMED)
+			return
+		}
+	}
+
+	defer b.Metrics.commandStarted(commandDefinition.CommandID)()
+
+	ctx, span := b.StartSpan(payload.Context, SPAN_CALLBACK, Field(LOG_FIELD_COMMAND, commandDefinition.CommandID))
+	defer span.End()
+
+	payload.Context = ctx
+
+	defer b.recoverSpanPanic(span, "Command panicked", append(b.messageLogFields(payload.Message), Field(LOG_FIELD_COMMAND, commandDefinition.CommandID))...)
+
+	commandDefinition.Callback(b, b.Client, payload)
+}
+
 func findTriggerMatch(commandDefinition *CommandDefinition, commandTrigger string, definitionPrefix string, messageParts []string, message Message) (bool, string) {
+	if len(messageParts) == 0 {
+		return false, ""
+	}
+
 	if messageParts[0] == definitionPrefix+commandTrigger {
 		return true, messageParts[0]
 	}
@@ -248,21 +679,55 @@ func findTriggerMatch(commandDefinition *CommandDefinition, commandTrigger strin
 	return false, ""
 }
 
-func validateCommandAccess(client *DiscordClient, commandDefinition *CommandDefinition, message Message) bool {
+// checkCommandAccess returns the reason a message can't use a command or an empty CommandDenial if it can
+func checkCommandAccess(b *Gobot, client *DiscordClient, commandDefinition *CommandDefinition, message Message) CommandDenial {
 	if commandDefinition.ExposureLevel > 0 {
 		switch commandDefinition.ExposureLevel {
 		case EXPOSURE_PRIVATE:
 			if !client.IsPrivate(message) {
-				return false
+				return DENIED_EXPOSURE
 			}
 		case EXPOSURE_PUBLIC:
 			if client.IsPrivate(message) {
-				return false
+				return DENIED_EXPOSURE
 			}
 		}
 	}
 
-	return validateCommandAccessPermission(client, commandDefinition.PermissionLevel, message)
+	for _, check := range commandDefinition.AccessChecks {
+		if check != nil && !check(b, client, message) {
+			return DENIED_PERMISSION
+		}
+	}
+
+	permissionLevel := commandDefinition.PermissionLevel
+	checkPermissionLevel := true
+
+	if override := b.getPermissionOverride(commandDefinition, message); override != nil && !client.IsBotOwner(message) {
+		if isPermissionOverrideDenied(client, override, message) {
+			return DENIED_PERMISSION
+		}
+
+		if isPermissionOverrideAllowed(client, override, message) {
+			checkPermissionLevel = false
+		} else if override.PermissionLevel > 0 {
+			permissionLevel = override.PermissionLevel
+		}
+	}
+
+	if checkPermissionLevel && !validateCommandAccessPermission(client, permissionLevel, message) {
+		return DENIED_PERMISSION
+	}
+
+	if len(commandDefinition.RequiredRoles) > 0 && !hasAnyRole(client, message, commandDefinition.RequiredRoles) {
+		return DENIED_PERMISSION
+	}
+
+	if commandDefinition.RequiredDiscordPermissions != 0 && !hasDiscordPermissions(client, message, commandDefinition.RequiredDiscordPermissions) {
+		return DENIED_PERMISSION
+	}
+
+	return ""
 }
 
 func validateCommandAccessPermission(client *DiscordClient, permissionLevel PermissionLevel, message Message) bool {
@@ -299,24 +764,7 @@ func extractCommandArguments(message Message, trigger string, arguments []Comman
 		return true, parsedArgs
 	}
 
-	var argPatterns []string
-
-	for i, argument := range arguments {
-		pattern := ""
-
-		if i == 0 {
-			pattern = fmt.Sprintf("(?P<%s>%s)", argument.Alias, argument.Pattern)
-		} else {
-			pattern = fmt.Sprintf("(?:\\s+(?P<%s>%s))", argument.Alias, argument.Pattern)
-		}
-
-		if argument.Optional {
-			pattern += "?"
-		}
-
-		argPatterns = append(argPatterns, pattern)
-	}
-	var pattern = fmt.Sprintf("^%s$", strings.Join(argPatterns, ""))
+	var pattern = commandArgumentsPattern(arguments)
 
 	var trimmedContent = strings.TrimSpace(strings.TrimPrefix(message.RawMessage(), fmt.Sprintf("%s", trigger)))
 	pat := regexp.MustCompile(pattern)
@@ -353,6 +801,10 @@ func handleCommandsRequest(b *Gobot, message Message, commandPrefix string) {
 	help := []string{}
 
 	for _, plugin := range b.Plugins {
+		if !b.isPluginLoaded(plugin) || !b.IsPluginEnabled(plugin, message) {
+			continue
+		}
+
 		var h []string
 
 		helpResult := plugin.Help(b, b.Client, message, false)
@@ -361,7 +813,7 @@ func handleCommandsRequest(b *Gobot, message Message, commandPrefix string) {
 			h = helpResult
 		} else if plugin.Commands() != nil {
 			for _, commandDefinition := range plugin.Commands() {
-				if commandDefinition.Unlisted {
+				if commandDefinition.Unlisted || !b.IsCommandEnabled(commandDefinition, message) {
 					continue
 				}
 
@@ -380,8 +832,8 @@ func handleCommandsRequest(b *Gobot, message Message, commandPrefix string) {
 		}
 	}
 
-	for _, commandDefinition := range b.Commands {
-		if commandDefinition.Unlisted {
+	for _, commandDefinition := range b.RegisteredCommands() {
+		if commandDefinition.Unlisted || !b.IsCommandEnabled(commandDefinition, message) {
 			continue
 		}
 
@@ -400,6 +852,17 @@ func handleCommandsRequest(b *Gobot, message Message, commandPrefix string) {
 		help = []string{"No commands found"}
 	}
 
+	if b.Config != nil && b.Config.CommandsPageSize > 0 {
+		paginator := NewPaginator(b, PaginateLines(help, b.Config.CommandsPageSize), message.UserID())
+
+		if b.Config.CommandsPageTimeout > 0 {
+			paginator.Timeout = b.Config.CommandsPageTimeout
+		}
+
+		paginator.Send(message.Channel())
+		return
+	}
+
 	b.Client.SendMessage(message.Channel(), strings.Join(help, "\n"))
 }
 
@@ -1,6 +1,15 @@
 package discordgobot
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// argumentAliasPattern matches aliases that can be used as regex capture group names
+var argumentAliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
 // CommandDefinition is the basic type for defining plugin commands
 type CommandDefinition struct {
@@ -14,8 +23,26 @@ type CommandDefinition struct {
 	Arguments []CommandDefinitionArgument
 	// PermissionLevel is the minimum level of command access. Default is PERMISSION_USER.
 	PermissionLevel PermissionLevel
+	// RequiredRoles restricts the command to users with at least one of these role IDs or names.
+	RequiredRoles []string
+	// RequiredDiscordPermissions is a discordgo permission bitmask the user must have in the channel, e.g. discordgo.PermissionManageMessages.
+	RequiredDiscordPermissions int
+	// AccessChecks are custom predicates that must all return true for the command to be processed.
+	AccessChecks []func(bot *Gobot, client *DiscordClient, message Message) bool
 	// ExposureLevel restricts commands from being processed in either public, private, or both settings. Default is EXPOSURE_EVERYWHERE.
 	ExposureLevel ExposureLevel
+	// Confirm asks the user to confirm before the callback is run. Default is false.
+	Confirm bool
+	// ConfirmPrompt is the question asked when Confirm is set. Default is DEFAULT_CONFIRM_PROMPT.
+	ConfirmPrompt string
+	// DeleteRepliesWithMessage deletes the replies sent with Gobot.Reply when the message that called the command is deleted. Default is false.
+	DeleteRepliesWithMessage bool
+	// SlashCommand exports the command as a slash command named after its first trigger. Default is false.
+	SlashCommand bool
+	// Audited records every use of the command in the audit log. Commands at PERMISSION_MODERATOR and above are always audited.
+	Audited bool
+	// Category groups the command in exported command references. Default is the plugin name.
+	Category string
 	// Unlisted prevents a command from being listed when a user calls the commands list. Default is false.
 	Unlisted bool
 	// DisableTriggerOnMention prevents a command from being triggered when a user uses @BotName. Default is false.
@@ -38,6 +65,13 @@ type CommandPayload struct {
 	Arguments map[string]string
 	// Trigger is the specific string that activated the command
 	Trigger string
+	// Context carries the trace of the command. Use it with Gobot.StartSpan to add child spans.
+	Context context.Context
+	// Edited is true when the command is run again because its message was edited
+	Edited bool
+
+	reply       *commandReply
+	interaction *Interaction
 }
 
 // CommandDefinitionArgument defines parameters to parse from message text
@@ -48,6 +82,8 @@ type CommandDefinitionArgument struct {
 	Pattern string
 	// Alias is the name of the parameter to return when the argument map is sent to the CommandDefinition Callback
 	Alias string
+	// Description explains the argument in slash command schemas. Default is the Alias.
+	Description string
 }
 
 // PermissionLevel access required to execute command
@@ -69,6 +105,45 @@ const (
 	EXPOSURE_PRIVATE
 )
 
+func (p PermissionLevel) String() string {
+	switch p {
+	case PERMISSION_OWNER:
+		return "owner"
+	case PERMISSION_ADMIN:
+		return "admin"
+	case PERMISSION_MODERATOR:
+		return "moderator"
+	case PERMISSION_USER, 0:
+		return "user"
+	}
+
+	return strconv.Itoa(int(p))
+}
+
+func (e ExposureLevel) String() string {
+	switch e {
+	case EXPOSURE_EVERYWHERE, 0:
+		return "everywhere"
+	case EXPOSURE_PUBLIC:
+		return "public"
+	case EXPOSURE_PRIVATE:
+		return "private"
+	}
+
+	return strconv.Itoa(int(e))
+}
+
+// CommandDenial is the reason a message that matched a command trigger didn't run the command
+type CommandDenial string
+
+const (
+	DENIED_DISABLED    CommandDenial = "disabled"
+	DENIED_EXPOSURE    CommandDenial = "exposure"
+	DENIED_PERMISSION  CommandDenial = "permission"
+	DENIED_ARGUMENTS   CommandDenial = "arguments"
+	DENIED_UNCONFIRMED CommandDenial = "unconfirmed"
+)
+
 // IsValid determines if the command definition is configured correctly
 func (c *CommandDefinition) IsValid() (bool, []string) {
 	errors := make([]string, 0)
@@ -86,14 +161,29 @@ func (c *CommandDefinition) IsValid() (bool, []string) {
 	}
 
 	if c.Arguments != nil && len(c.Arguments) > 0 {
+		argumentsValid := true
+		aliases := make(map[string]bool)
+
 		for _, argument := range c.Arguments {
 			if isValid, argErrors := argument.IsValid(); !isValid {
 				errors = append(errors, argErrors...)
+				argumentsValid = false
+			}
+
+			if aliases[argument.Alias] {
+				errors = append(errors, fmt.Sprintf("Argument alias '%s' is used more than once", argument.Alias))
+			}
+			aliases[argument.Alias] = true
+		}
+
+		if argumentsValid {
+			if _, err := regexp.Compile(commandArgumentsPattern(c.Arguments)); err != nil {
+				errors = append(errors, fmt.Sprintf("Invalid argument patterns for CommandDefinition: %v", err))
 			}
 		}
 	}
 
-	return len(errors) > 0, errors
+	return len(errors) == 0, errors
 }
 
 // Help generates a help string from a CommandDefinition
@@ -120,9 +210,34 @@ func (c *CommandDefinitionArgument) IsValid() (bool, []string) {
 
 	if c.Alias == "" {
 		errors = append(errors, "No argument alias provided for CommandDefinitionArgument")
+	} else if !argumentAliasPattern.MatchString(c.Alias) {
+		errors = append(errors, fmt.Sprintf("Argument alias '%s' may only contain letters, digits and underscores and can't start with a digit", c.Alias))
+	}
+
+	return len(errors) == 0, errors
+}
+
+// commandArgumentsPattern combines the patterns of a command's arguments into the regex used to parse a message
+func commandArgumentsPattern(arguments []CommandDefinitionArgument) string {
+	var argPatterns []string
+
+	for i, argument := range arguments {
+		pattern := ""
+
+		if i == 0 {
+			pattern = fmt.Sprintf("(?P<%s>%s)", argument.Alias, argument.Pattern)
+		} else {
+			pattern = fmt.Sprintf("(?:\\s+(?P<%s>%s))", argument.Alias, argument.Pattern)
+		}
+
+		if argument.Optional {
+			pattern += "?"
+		}
+
+		argPatterns = append(argPatterns, pattern)
 	}
 
-	return len(errors) > 0, errors
+	return fmt.Sprintf("^%s$", strings.Join(argPatterns, ""))
 }
 
 // CommandHelp is a helper message that creates help text for a command.
@@ -138,7 +253,7 @@ func CommandHelp(client *DiscordClient, command string, arguments []string, desc
 	return fmt.Sprintf("`%s` - %s", commandString, description)
 }
 
-func validateCommand(command *CommandDefinition) bool {
+func validateCommand(logger Logger, command *CommandDefinition) bool {
 	errors := make([]string, 0)
 
 	if isValid, commandErrors := command.IsValid(); !isValid {
@@ -147,7 +262,7 @@ func validateCommand(command *CommandDefinition) bool {
 
 	if len(errors) > 0 {
 		for _, errmsg := range errors {
-			fmt.Printf("Command validation error: %s: %s", command.CommandID, errmsg)
+			logger.Log(LOG_ERROR, "Command validation error", Field(LOG_FIELD_COMMAND, command.CommandID), Field(LOG_FIELD_ERROR, errmsg))
 		}
 		return false
 	}
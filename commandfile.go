@@ -0,0 +1,352 @@
+package discordgobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// COMMAND_FILE_ID_PREFIX prefixes the CommandID of commands loaded from a file that don't set an id
+const COMMAND_FILE_ID_PREFIX = "gobot-file-"
+
+var exposureLevelNames = map[string]ExposureLevel{
+	"everywhere": EXPOSURE_EVERYWHERE,
+	"public":     EXPOSURE_PUBLIC,
+	"private":    EXPOSURE_PRIVATE,
+}
+
+// CommandFile is the document read by LoadCommandFile
+type CommandFile struct {
+	Commands []CommandFileDefinition `json:"commands" yaml:"commands"`
+}
+
+// CommandFileDefinition declares a command that replies with a templated response
+type CommandFileDefinition struct {
+	// ID is the CommandID of the command. Defaults to COMMAND_FILE_ID_PREFIX followed by the first trigger.
+	ID          string   `json:"id" yaml:"id"`
+	Description string   `json:"description" yaml:"description"`
+	Triggers    []string `json:"triggers" yaml:"triggers"`
+	Prefix      string   `json:"prefix" yaml:"prefix"`
+	Category    string   `json:"category" yaml:"category"`
+	// Permission is one of owner, admin, moderator or user. Defaults to user.
+	Permission string `json:"permission" yaml:"permission"`
+	// Exposure is one of everywhere, public or private. Defaults to everywhere.
+	Exposure  string                          `json:"exposure" yaml:"exposure"`
+	Arguments []CommandFileDefinitionArgument `json:"arguments" yaml:"arguments"`
+	Unlisted  bool                            `json:"unlisted" yaml:"unlisted"`
+	// Response is a text/template executed with CommandFileResponseData
+	Response string `json:"response" yaml:"response"`
+}
+
+// CommandFileDefinitionArgument declares an argument of a CommandFileDefinition
+type CommandFileDefinitionArgument struct {
+	Name        string `json:"name" yaml:"name"`
+	Pattern     string `json:"pattern" yaml:"pattern"`
+	Description string `json:"description" yaml:"description"`
+	Optional    bool   `json:"optional" yaml:"optional"`
+}
+
+// CommandFileResponseData is available to the response templates of commands loaded from a file
+type CommandFileResponseData struct {
+	UserID    string
+	UserName  string
+	ChannelID string
+	GuildID   string
+	Trigger   string
+	Args      map[string]string
+}
+
+// Mention returns the mention of the user that called the command
+func (d CommandFileResponseData) Mention() string {
+	return fmt.Sprintf("<@%s>", d.UserID)
+}
+
+type commandFiles struct {
+	sync.Mutex
+	files map[string]*loadedCommandFile
+}
+
+type loadedCommandFile struct {
+	modTime    time.Time
+	commandIDs []string
+}
+
+// LoadCommandFile registers the commands declared in a YAML or JSON file. Files ending in .json are read as JSON and
+// everything else as YAML. Loading a file again replaces the commands it registered before. Nothing is changed if the file is invalid.
+func (b *Gobot) LoadCommandFile(fileName string) error {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	var file CommandFile
+
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		err = yaml.UnmarshalStrict(data, &file)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error reading command file '%s': %v", fileName, err)
+	}
+
+	commands := make([]*CommandDefinition, 0, len(file.Commands))
+	commandIDs := make([]string, 0, len(file.Commands))
+	seen := make(map[string]bool)
+
+	for i, definition := range file.Commands {
+		command, err := definition.commandDefinition()
+		if err != nil {
+			return fmt.Errorf("Error in command %d of '%s': %v", i+1, fileName, err)
+		}
+
+		if !validateCommand(b.Logger(), command) {
+			return fmt.Errorf("A misconfigured command was found in '%s': '%s'", fileName, command.CommandID)
+		}
+
+		if seen[command.CommandID] {
+			return fmt.Errorf("Command id '%s' is used more than once in '%s'", command.CommandID, fileName)
+		}
+		seen[command.CommandID] = true
+
+		commands = append(commands, command)
+		commandIDs = append(commandIDs, command.CommandID)
+	}
+
+	b.commandFiles.Lock()
+	defer b.commandFiles.Unlock()
+
+	if b.commandFiles.files == nil {
+		b.commandFiles.files = make(map[string]*loadedCommandFile)
+	}
+
+	var previousIDs []string
+	if loaded := b.commandFiles.files[fileName]; loaded != nil {
+		previousIDs = loaded.commandIDs
+	}
+
+	if err := b.replaceFileCommands(previousIDs, commands); err != nil {
+		return fmt.Errorf("Error loading command file '%s': %v", fileName, err)
+	}
+
+	b.commandFiles.files[fileName] = &loadedCommandFile{
+		modTime:    info.ModTime(),
+		commandIDs: commandIDs,
+	}
+
+	b.logInfo("Loaded command file", Field("file", fileName), Field("commands", len(commands)))
+
+	return nil
+}
+
+// replaceFileCommands swaps the commands a file registered before for its new commands in one step so they're never
+// missing while messages are dispatched. Commands registered by anything else can't be replaced.
+func (b *Gobot) replaceFileCommands(previousIDs []string, commands []*CommandDefinition) error {
+	previous := make(map[string]bool)
+	for _, commandID := range previousIDs {
+		previous[commandID] = true
+	}
+
+	for _, plugin := range b.Plugins {
+		for _, pluginCommand := range plugin.Commands() {
+			for _, command := range commands {
+				if pluginCommand.CommandID == command.CommandID {
+					return fmt.Errorf("Command id '%s' is already used by plugin '%s'", command.CommandID, plugin.Name())
+				}
+			}
+		}
+	}
+
+	b.commandMutex.Lock()
+	defer b.commandMutex.Unlock()
+
+	for _, command := range commands {
+		if b.Commands[command.CommandID] != nil && !previous[command.CommandID] {
+			return fmt.Errorf("Command id '%s' is already registered", command.CommandID)
+		}
+	}
+
+	for commandID := range previous {
+		delete(b.Commands, commandID)
+	}
+
+	for _, command := range commands {
+		b.Commands[command.CommandID] = command
+	}
+
+	return nil
+}
+
+func (b *Gobot) startCommandFileWatch() {
+	if b.Config == nil || b.Config.CommandFile == "" || b.Config.CommandFileReloadInterval <= 0 || b.stopFileWatch != nil {
+		return
+	}
+
+	b.stopFileWatch = make(chan bool)
+
+	go func(fileName string, interval time.Duration, stop chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				b.reloadCommandFile(fileName)
+			case <-stop:
+				return
+			}
+		}
+	}(b.Config.CommandFile, b.Config.CommandFileReloadInterval, b.stopFileWatch)
+}
+
+// reloadCommandFile loads a command file again if it was modified. The previous commands are kept if it's invalid.
+func (b *Gobot) reloadCommandFile(fileName string) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		b.logError("Error checking command file", err, Field("file", fileName))
+		return
+	}
+
+	b.commandFiles.Lock()
+	loaded := b.commandFiles.files[fileName]
+	b.commandFiles.Unlock()
+
+	if loaded != nil && loaded.modTime.Equal(info.ModTime()) {
+		return
+	}
+
+	if err := b.LoadCommandFile(fileName); err != nil {
+		b.logError("Error reloading command file", err, Field("file", fileName))
+	}
+}
+
+func (d CommandFileDefinition) commandDefinition() (*CommandDefinition, error) {
+	if len(d.Triggers) == 0 {
+		return nil, fmt.Errorf("No triggers provided")
+	}
+
+	commandID := d.ID
+	if commandID == "" {
+		commandID = COMMAND_FILE_ID_PREFIX + d.Triggers[0]
+	}
+
+	permissionLevel := PERMISSION_USER
+	if d.Permission != "" {
+		level, ok := permissionLevelNames[strings.ToLower(d.Permission)]
+		if !ok {
+			return nil, fmt.Errorf("Permission of '%s' must be one of owner, admin, moderator or user", commandID)
+		}
+		permissionLevel = level
+	}
+
+	exposureLevel := EXPOSURE_EVERYWHERE
+	if d.Exposure != "" {
+		level, ok := exposureLevelNames[strings.ToLower(d.Exposure)]
+		if !ok {
+			return nil, fmt.Errorf("Exposure of '%s' must be one of everywhere, public or private", commandID)
+		}
+		exposureLevel = level
+	}
+
+	if d.Response == "" {
+		return nil, fmt.Errorf("No response provided for '%s'", commandID)
+	}
+
+	response, err := template.New(commandID).Parse(d.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]CommandDefinitionArgument, len(d.Arguments))
+	names := make(map[string]bool)
+	for i, argument := range d.Arguments {
+		if !argumentAliasPattern.MatchString(argument.Name) {
+			return nil, fmt.Errorf("Argument name '%s' of '%s' may only contain letters, digits and underscores and can't start with a digit", argument.Name, commandID)
+		}
+
+		if names[argument.Name] {
+			return nil, fmt.Errorf("Argument name '%s' is used more than once in '%s'", argument.Name, commandID)
+		}
+		names[argument.Name] = true
+
+		if _, err := regexp.Compile(argument.Pattern); err != nil {
+			return nil, fmt.Errorf("Invalid pattern for argument '%s' of '%s': %v", argument.Name, commandID, err)
+		}
+
+		arguments[i] = CommandDefinitionArgument{
+			Alias:       argument.Name,
+			Pattern:     argument.Pattern,
+			Description: argument.Description,
+			Optional:    argument.Optional,
+		}
+	}
+
+	return &CommandDefinition{
+		CommandID:       commandID,
+		Description:     d.Description,
+		Triggers:        d.Triggers,
+		Arguments:       arguments,
+		CommandPrefix:   d.Prefix,
+		Category:        d.Category,
+		PermissionLevel: permissionLevel,
+		ExposureLevel:   exposureLevel,
+		Unlisted:        d.Unlisted,
+		Callback:        templateResponseCallback(response),
+	}, nil
+}
+
+func templateResponseCallback(response *template.Template) func(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	return func(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+		guildID, _ := payload.Message.ResolveGuildID()
+
+		data := CommandFileResponseData{
+			UserID:    payload.Message.UserID(),
+			UserName:  escapeMentions(payload.Message.UserName()),
+			ChannelID: payload.Message.Channel(),
+			GuildID:   guildID,
+			Trigger:   payload.Trigger,
+			Args:      make(map[string]string, len(payload.Arguments)),
+		}
+
+		for name, value := range payload.Arguments {
+			data.Args[name] = escapeMentions(value)
+		}
+
+		var sb strings.Builder
+		if err := response.Execute(&sb, data); err != nil {
+			bot.logError("Error executing command response", err, Field(LOG_FIELD_COMMAND, payload.CommandID))
+			return
+		}
+
+		if strings.TrimSpace(sb.String()) == "" {
+			return
+		}
+
+		if _, err := bot.Reply(payload, sb.String()); err != nil {
+			bot.logError("Error sending command response", err, Field(LOG_FIELD_COMMAND, payload.CommandID))
+		}
+	}
+}
+
+// escapeMentions stops user provided text from mentioning anyone, e.g. with @everyone, by adding a zero width space after each @
+func escapeMentions(text string) string {
+	return strings.Replace(text, "@", "@\u200b", -1)
+}
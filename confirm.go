@@ -0,0 +1,136 @@
+package discordgobot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// CONFIRM_YES is the reaction used to confirm a prompt
+	CONFIRM_YES = "✅"
+	// CONFIRM_NO is the reaction used to reject a prompt
+	CONFIRM_NO = "❌"
+	// DEFAULT_CONFIRM_TIMEOUT is how long a confirmation prompt waits if no timeout is configured
+	DEFAULT_CONFIRM_TIMEOUT = 30 * time.Second
+	// DEFAULT_CONFIRM_PROMPT is the prompt sent for commands with Confirm set and no ConfirmPrompt
+	DEFAULT_CONFIRM_PROMPT = "Are you sure?"
+)
+
+var (
+	confirmYesWords = []string{"yes", "y"}
+	confirmNoWords  = []string{"no", "n"}
+)
+
+// Confirm asks the author of a message to confirm a prompt by replying yes or no or reacting with CONFIRM_YES or CONFIRM_NO.
+// The context's error is returned if the author doesn't answer within timeout. DEFAULT_CONFIRM_TIMEOUT is used when timeout is 0.
+func (b *Gobot) Confirm(ctx context.Context, message Message, prompt string, timeout time.Duration) (bool, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if timeout <= 0 {
+		timeout = DEFAULT_CONFIRM_TIMEOUT
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	channelID := message.Channel()
+	userID := message.UserID()
+
+	m, err := b.Client.Session.ChannelMessageSend(channelID, prompt+" Reply `yes` or `no`, or react "+CONFIRM_YES+" / "+CONFIRM_NO+".")
+	if err != nil {
+		return false, err
+	}
+
+	answers := make(chan bool, 2)
+
+	b.AddReactionListener(m.ID, func(bot *Gobot, client *DiscordClient, reaction *discordgo.MessageReaction) {
+		if reaction.UserID != userID {
+			return
+		}
+
+		switch reaction.Emoji.Name {
+		case CONFIRM_YES:
+			sendAnswer(answers, true)
+		case CONFIRM_NO:
+			sendAnswer(answers, false)
+		}
+	})
+	defer b.RemoveReactionListener(m.ID)
+
+	b.Client.Session.MessageReactionAdd(channelID, m.ID, CONFIRM_YES)
+	b.Client.Session.MessageReactionAdd(channelID, m.ID, CONFIRM_NO)
+
+	go func() {
+		reply, err := b.AwaitMessage(ctx, func(candidate Message) bool {
+			if candidate.UserID() != userID || candidate.Channel() != channelID {
+				return false
+			}
+
+			content := strings.TrimSpace(candidate.Message())
+
+			return matchesWord(content, confirmYesWords) || matchesWord(content, confirmNoWords) || b.checkCancelWord(candidate) != nil
+		})
+		if reply == nil {
+			return
+		}
+
+		sendAnswer(answers, err == nil && matchesWord(strings.TrimSpace(reply.Message()), confirmYesWords))
+	}()
+
+	select {
+	case confirmed := <-answers:
+		return confirmed, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// confirmCommand asks the user to confirm a command with Confirm set. Returns true if the callback should run.
+func (b *Gobot) confirmCommand(commandDefinition *CommandDefinition, payload CommandPayload) bool {
+	prompt := commandDefinition.ConfirmPrompt
+	if prompt == "" {
+		prompt = DEFAULT_CONFIRM_PROMPT
+	}
+
+	var timeout time.Duration
+	if b.Config != nil {
+		timeout = b.Config.ConfirmTimeout
+	}
+
+	confirmed, err := b.Confirm(payload.Context, payload.Message, prompt, timeout)
+	if err == context.DeadlineExceeded {
+		b.Client.SendMessage(payload.Message.Channel(), "No answer received, the command was not run.")
+		return false
+	} else if err != nil {
+		b.logError("Error confirming command", err, append(b.messageLogFields(payload.Message), Field(LOG_FIELD_COMMAND, commandDefinition.CommandID))...)
+		return false
+	}
+
+	if !confirmed {
+		b.Client.SendMessage(payload.Message.Channel(), "Cancelled.")
+	}
+
+	return confirmed
+}
+
+func sendAnswer(answers chan bool, answer bool) {
+	select {
+	case answers <- answer:
+	default:
+	}
+}
+
+func matchesWord(content string, words []string) bool {
+	for _, word := range words {
+		if strings.EqualFold(content, word) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package discordgobot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lampjaw/discordclient"
+)
+
+// DEFAULT_AWAIT_TIMEOUT is how long AwaitReply waits if no timeout is given
+const DEFAULT_AWAIT_TIMEOUT = 2 * time.Minute
+
+// DEFAULT_CANCEL_WORDS end a conversation if no CancelWords are configured
+var DEFAULT_CANCEL_WORDS = []string{"cancel", "stop"}
+
+// ErrConversationCancelled is returned when the awaited reply is a cancel word
+var ErrConversationCancelled = errors.New("Conversation cancelled")
+
+// MessageFilter selects which messages are intercepted by AwaitMessage. A nil filter matches every message.
+type MessageFilter func(message Message) bool
+
+type messageWaiter struct {
+	filter MessageFilter
+	result chan Message
+}
+
+type messageWaiters struct {
+	sync.Mutex
+	waiters []*messageWaiter
+}
+
+// FromUserInChannel creates a MessageFilter matching messages sent by a user in a channel
+func FromUserInChannel(userID string, channelID string) MessageFilter {
+	return func(message Message) bool {
+		return message.UserID() == userID && message.Channel() == channelID
+	}
+}
+
+// AwaitMessage intercepts the next message that matches filter before it reaches plugins and commands.
+// It returns the context's error if ctx is done first and ErrConversationCancelled along with the message if it is a cancel word.
+func (b *Gobot) AwaitMessage(ctx context.Context, filter MessageFilter) (Message, error) {
+	waiter := &messageWaiter{
+		filter: filter,
+		result: make(chan Message, 1),
+	}
+
+	b.conversations.add(waiter)
+
+	select {
+	case message := <-waiter.result:
+		return message, b.checkCancelWord(message)
+	case <-ctx.Done():
+		if b.conversations.remove(waiter) {
+			return nil, ctx.Err()
+		}
+
+		// The message was intercepted while the context finished
+		message := <-waiter.result
+		return message, b.checkCancelWord(message)
+	}
+}
+
+// AwaitReply waits for the next message from the author of a message in the same channel.
+// DEFAULT_AWAIT_TIMEOUT is used when timeout is 0.
+func (b *Gobot) AwaitReply(ctx context.Context, message Message, timeout time.Duration) (Message, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if timeout <= 0 {
+		timeout = DEFAULT_AWAIT_TIMEOUT
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return b.AwaitMessage(ctx, FromUserInChannel(message.UserID(), message.Channel()))
+}
+
+// Ask sends a question to the channel of a message and waits for its author to reply
+func (b *Gobot) Ask(ctx context.Context, message Message, question string, timeout time.Duration) (Message, error) {
+	if err := b.Client.SendMessage(message.Channel(), question); err != nil {
+		return nil, err
+	}
+
+	return b.AwaitReply(ctx, message, timeout)
+}
+
+// interceptMessage hands a message to the oldest waiter it matches. Returns true if the message was intercepted.
+func (b *Gobot) interceptMessage(message Message) bool {
+	if message.Type() != discordclient.MessageTypeCreate || b.Client.IsMe(message) {
+		return false
+	}
+
+	return b.conversations.deliver(message)
+}
+
+func (b *Gobot) checkCancelWord(message Message) error {
+	cancelWords := DEFAULT_CANCEL_WORDS
+	if b.Config != nil && b.Config.CancelWords != nil {
+		cancelWords = b.Config.CancelWords
+	}
+
+	if matchesWord(strings.TrimSpace(message.Message()), cancelWords) {
+		return ErrConversationCancelled
+	}
+
+	return nil
+}
+
+func (w *messageWaiters) add(waiter *messageWaiter) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.waiters = append(w.waiters, waiter)
+}
+
+// remove stops a waiter from receiving messages. Returns false if it already received one.
+func (w *messageWaiters) remove(waiter *messageWaiter) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	for i, existing := range w.waiters {
+		if existing == waiter {
+			w.waiters = append(w.waiters[:i], w.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *messageWaiters) deliver(message Message) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	for i, waiter := range w.waiters {
+		if waiter.filter != nil && !waiter.filter(message) {
+			continue
+		}
+
+		w.waiters = append(w.waiters[:i], w.waiters[i+1:]...)
+		waiter.result <- message
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,241 @@
+package discordgobot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five field cron expression: minute, hour, day of month, month and day of week
+type CronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	anyDay      bool
+	anyWeekday  bool
+	fixedTime   bool
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinutes     = cronField{min: 0, max: 59}
+	cronHours       = cronField{min: 0, max: 23}
+	cronDaysOfMonth = cronField{min: 1, max: 31}
+	cronMonths      = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDaysOfWeek = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// maxCronSearchYears bounds the search for the next run of an expression that can never match, e.g. "0 0 31 2 *"
+const maxCronSearchYears = 5
+
+// ParseCron parses a cron expression such as "*/15 9-17 * * mon-fri"
+func ParseCron(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Cron expression '%s' must have 5 fields", expression)
+	}
+
+	schedule := &CronSchedule{
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+		fixedTime:  !strings.HasPrefix(fields[0], "*") && !strings.HasPrefix(fields[1], "*"),
+	}
+
+	var err error
+
+	if schedule.minutes, err = cronMinutes.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = cronHours.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfMonth, err = cronDaysOfMonth.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = cronMonths.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfWeek, err = cronDaysOfWeek.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// 7 is an alias for sunday
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+// Next returns the first time after t that matches the schedule or the zero time if none is found.
+// Like cron, expressions with a fixed minute and hour run once when daylight saving repeats an hour and run as soon as
+// the clock changes when daylight saving skips their hour. Other expressions follow the clock.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxCronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = advanceCron(t, cronDate(t.Year(), t.Month()+1, 1, 0, t.Location()))
+			continue
+		}
+
+		if !c.matchesDay(t) {
+			t = advanceCron(t, cronDate(t.Year(), t.Month(), t.Day()+1, 0, t.Location()))
+			continue
+		}
+
+		if c.fixedTime && c.skippedHourMatches(t) {
+			return t
+		}
+
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			t = advanceCron(t, cronDate(t.Year(), t.Month(), t.Day(), t.Hour()+1, t.Location()))
+			continue
+		}
+
+		if c.minutes&(1<<uint(t.Minute())) == 0 || (c.fixedTime && isRepeatedWallClock(t)) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// skippedHourMatches determines if t is the first minute after daylight saving skipped an hour of the schedule
+func (c *CronSchedule) skippedHourMatches(t time.Time) bool {
+	previous := t.Add(-time.Minute)
+	if previous.Day() != t.Day() {
+		return false
+	}
+
+	for hour := previous.Hour() + 1; hour < t.Hour(); hour++ {
+		if c.hours&(1<<uint(hour)) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cronDate returns the first instant at a wall clock time. time.Date doesn't guarantee which instant is returned
+// when daylight saving makes a time ambiguous.
+func cronDate(year int, month time.Month, day int, hour int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, loc)
+
+	if earlier := t.Add(-time.Hour); isSameWallClock(earlier, t) {
+		return earlier
+	}
+
+	return t
+}
+
+// advanceCron moves the search to next, or a minute on when daylight saving puts next at or before t
+func advanceCron(t time.Time, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+
+	return t.Add(time.Minute)
+}
+
+// isRepeatedWallClock determines if the wall clock time of t already happened an hour earlier because daylight saving ended
+func isRepeatedWallClock(t time.Time) bool {
+	return isSameWallClock(t.Add(-time.Hour), t)
+}
+
+func isSameWallClock(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay() && a.Hour() == b.Hour() && a.Minute() == b.Minute()
+}
+
+// matchesDay follows cron in matching either field when both the day of month and day of week are restricted
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return dayOfWeek
+	case c.anyWeekday:
+		return dayOfMonth
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("Invalid step in cron field '%s'", field)
+			}
+			part = part[:i]
+		}
+
+		start, end := f.min, f.max
+
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+
+			end = start
+			if len(bounds) == 2 {
+				if end, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				end = f.max
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("Invalid range in cron field '%s'", field)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("Cron value '%s' must be between %d and %d", s, f.min, f.max)
+	}
+
+	return v, nil
+}
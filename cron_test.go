@@ -0,0 +1,116 @@
+package discordgobot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		valid      bool
+	}{
+		{"* * * * *", true},
+		{"*/15 9-17 * * mon-fri", true},
+		{"0 0 1 jan *", true},
+		{"0,30 */2 1-15/2 * SUN", true},
+		{"0 0 * * 7", true},
+		{"* * * *", false},
+		{"* * * * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"5-1 * * * *", false},
+		{"*/0 * * * *", false},
+		{"* * * foo *", false},
+		{"1-2-3 * * * *", false},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCron(test.expression)
+		if test.valid && err != nil {
+			t.Errorf("ParseCron(%q) returned %v", test.expression, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("ParseCron(%q) should fail", test.expression)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	utc := func(year int, month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"* * * * *", utc(2026, 1, 1, 10, 0).Add(30 * time.Second), utc(2026, 1, 1, 10, 1)},
+		{"* * * * *", utc(2026, 1, 1, 10, 0), utc(2026, 1, 1, 10, 1)},
+		{"*/15 * * * *", utc(2026, 1, 1, 10, 7), utc(2026, 1, 1, 10, 15)},
+		{"*/15 * * * *", utc(2026, 1, 1, 23, 50), utc(2026, 1, 2, 0, 0)},
+		{"0 9 * * mon-fri", utc(2026, 1, 2, 10, 0), utc(2026, 1, 5, 9, 0)},
+		{"0 0 1 * *", utc(2026, 1, 15, 0, 0), utc(2026, 2, 1, 0, 0)},
+		{"0 0 1 jan *", utc(2026, 6, 1, 0, 0), utc(2027, 1, 1, 0, 0)},
+		{"30 12 29 2 *", utc(2026, 1, 1, 0, 0), utc(2028, 2, 29, 12, 30)},
+		{"0 0 * * 7", utc(2026, 1, 1, 0, 0), utc(2026, 1, 4, 0, 0)},
+		{"0 0 * * sun", utc(2026, 1, 1, 0, 0), utc(2026, 1, 4, 0, 0)},
+		// Either the day of month or the day of week matches when both are restricted
+		{"0 0 13 * fri", utc(2026, 1, 1, 0, 0), utc(2026, 1, 2, 0, 0)},
+		{"0 0 13 * fri", utc(2026, 1, 9, 0, 0), utc(2026, 1, 13, 0, 0)},
+		{"0 0 31 2 *", utc(2026, 1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCron(test.expression)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) returned %v", test.expression, err)
+		}
+
+		if got := schedule.Next(test.from); !got.Equal(test.want) {
+			t.Errorf("%q.Next(%v) = %v, want %v", test.expression, test.from, got, test.want)
+		}
+	}
+}
+
+func TestCronScheduleNextDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+
+	// Clocks go from 02:00 EST to 03:00 EDT on 2026-03-08 and from 02:00 EDT to 01:00 EST on 2026-11-01
+	at := func(year int, month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC).In(newYork)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"skipped hour runs when the clock changes", "30 2 * * *", at(2026, 3, 8, 5, 0), at(2026, 3, 8, 7, 0)},
+		{"skipped hour runs at its time the next day", "30 2 * * *", at(2026, 3, 8, 7, 0), at(2026, 3, 9, 6, 30)},
+		{"interval follows the clock over the skipped hour", "*/30 * * * *", at(2026, 3, 8, 6, 30), at(2026, 3, 8, 7, 0)},
+		{"repeated hour runs the first time", "30 1 * * *", at(2026, 11, 1, 4, 0), at(2026, 11, 1, 5, 30)},
+		{"repeated hour doesn't run twice", "30 1 * * *", at(2026, 11, 1, 5, 30), at(2026, 11, 2, 6, 30)},
+		{"hourly runs in both repeated hours", "30 * * * *", at(2026, 11, 1, 5, 30), at(2026, 11, 1, 6, 30)},
+		{"midnight after the change", "0 0 * * *", at(2026, 11, 1, 4, 0), at(2026, 11, 2, 5, 0)},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCron(test.expression)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) returned %v", test.expression, err)
+		}
+
+		if got := schedule.Next(test.from); !got.Equal(test.want) {
+			t.Errorf("%s: %q.Next(%v) = %v, want %v", test.name, test.expression, test.from, got, test.want)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package discordgobot
 
 import (
 	"errors"
-	"fmt"
 
 	"github.com/lampjaw/discordclient"
 )
@@ -13,7 +12,11 @@ const VERSION = "0.4.0"
 // NewBot creates a new Gobot
 func NewBot(token string, config *GobotConf, state interface{}) (b *Gobot, err error) {
 	if token == "" {
-		fmt.Println("No token provided.")
+		if config != nil && config.Logger != nil {
+			config.Logger.Log(LOG_ERROR, "No token provided.")
+		} else {
+			defaultLogger.Log(LOG_ERROR, "No token provided.")
+		}
 		return nil, errors.New("Missing discord token")
 	}
 
@@ -25,6 +28,72 @@ func NewBot(token string, config *GobotConf, state interface{}) (b *Gobot, err e
 		Commands: make(map[string]*CommandDefinition, 0),
 		Config:   config,
 		State:    state,
+
+		reactionListeners: make(map[string]ReactionListener),
+	}
+
+	if state != nil {
+		bot.RegisterService(state)
+	}
+
+	if config.BuiltinCommandsEnabled && !config.PluginStatusCommandDisabled {
+		bot.registerPluginStatusCommand()
+	}
+
+	if config.MetricsEnabled || config.MetricsAddress != "" {
+		bot.Metrics = NewMetrics()
+	}
+
+	bot.Storage = config.Storage
+	if bot.Storage == nil {
+		if config.StorageDirectory != "" {
+			bot.Storage = NewFileStorage(config.StorageDirectory)
+		} else {
+			bot.Storage = NewMemoryStorage()
+		}
+	}
+
+	if config.AuditEnabled {
+		bot.Audit = NewAuditLog(bot.Storage)
+
+		if config.AuditMaxAge > 0 {
+			bot.Audit.MaxAge = config.AuditMaxAge
+		}
+
+		if config.AuditMaxEntries > 0 {
+			bot.Audit.MaxEntries = config.AuditMaxEntries
+		}
+
+		if !config.AuditCommandsDisabled {
+			bot.registerAuditCommands()
+		}
+	}
+
+	bot.Permissions = config.PermissionStore
+	if bot.Permissions == nil {
+		bot.Permissions = NewMemoryPermissionStore()
+	}
+
+	if config.BuiltinCommandsEnabled && !config.PermissionCommandsDisabled {
+		bot.registerPermissionCommands()
+	}
+
+	bot.Enablement = config.EnablementStore
+	if bot.Enablement == nil {
+		bot.Enablement = NewMemoryEnablementStore()
+	}
+
+	if config.BuiltinCommandsEnabled && !config.EnablementCommandsDisabled {
+		bot.registerEnablementCommands()
+	}
+
+	bot.Prefixes = config.PrefixStore
+	if bot.Prefixes == nil {
+		bot.Prefixes = NewMemoryPrefixStore()
+	}
+
+	if config.BuiltinCommandsEnabled && !config.PrefixCommandsDisabled {
+		bot.registerPrefixCommands()
 	}
 
 	return bot, nil
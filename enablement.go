@@ -0,0 +1,145 @@
+package discordgobot
+
+import (
+	"fmt"
+	"strings"
+)
+
+const enablementCommandID = "gobot-cmd-enablement"
+
+// IsPluginEnabled checks if a plugin is enabled for the channel and guild of a message
+func (b *Gobot) IsPluginEnabled(plugin IPlugin, message Message) bool {
+	return b.isEnabled(pluginEnablementName(plugin.Name()), message)
+}
+
+// IsCommandEnabled checks if a command is enabled for the channel and guild of a message
+func (b *Gobot) IsCommandEnabled(commandDefinition *CommandDefinition, message Message) bool {
+	return b.isEnabled(commandEnablementName(commandDefinition.CommandID), message)
+}
+
+func (b *Gobot) isEnabled(name string, message Message) bool {
+	if b.Enablement == nil {
+		return true
+	}
+
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return true
+	}
+
+	return b.isEnabledIn(name, guildID, message.Channel())
+}
+
+// isEnabledIn checks if a plugin or command is enabled for a channel and guild. The guild setting is used when channelID is empty.
+func (b *Gobot) isEnabledIn(name string, guildID string, channelID string) bool {
+	if b.Enablement == nil || guildID == "" {
+		return true
+	}
+
+	for _, channelID := range []string{channelID, ""} {
+		enabled, isSet, err := b.Enablement.GetEnabled(guildID, channelID, name)
+		if err != nil {
+			b.logError("Error reading enabled state", err, Field(LOG_FIELD_GUILD, guildID), Field(LOG_FIELD_CHANNEL, channelID), Field("name", name))
+			return true
+		}
+
+		if isSet {
+			return enabled
+		}
+	}
+
+	return true
+}
+
+func pluginEnablementName(pluginName string) string {
+	return "plugin:" + pluginName
+}
+
+func commandEnablementName(commandID string) string {
+	return "command:" + commandID
+}
+
+func (b *Gobot) registerEnablementCommands() {
+	b.registerBuiltinCommand(&CommandDefinition{
+		CommandID:   enablementCommandID,
+		Description: "Enables or disables a plugin or command for this server or a channel",
+		Triggers: []string{
+			"enable",
+			"disable",
+		},
+		Arguments: []CommandDefinitionArgument{
+			{
+				Pattern: "plugin|command",
+				Alias:   "kind",
+			},
+			{
+				Pattern: "\\S+",
+				Alias:   "name",
+			},
+			{
+				Pattern:  "server|here|<#[0-9]+>",
+				Alias:    "scope",
+				Optional: true,
+			},
+		},
+		PermissionLevel: PERMISSION_ADMIN,
+		ExposureLevel:   EXPOSURE_PUBLIC,
+		Callback:        handleEnablementCommand,
+	})
+}
+
+func handleEnablementCommand(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	channelID := payload.Message.Channel()
+
+	if bot.Enablement == nil {
+		client.SendMessage(channelID, "Enabling and disabling is not available.")
+		return
+	}
+
+	guildID, err := payload.Message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		client.SendMessage(channelID, "Plugins and commands can only be enabled or disabled in a server.")
+		return
+	}
+
+	var name, displayName string
+
+	switch payload.Arguments["kind"] {
+	case "plugin":
+		plugin := bot.Plugins[payload.Arguments["name"]]
+		if plugin == nil {
+			client.SendMessage(channelID, fmt.Sprintf("Unknown plugin `%s`.", payload.Arguments["name"]))
+			return
+		}
+		name, displayName = pluginEnablementName(plugin.Name()), plugin.Name()
+	case "command":
+		commandDefinition := bot.FindCommandDefinition(payload.Arguments["name"])
+		if commandDefinition == nil {
+			client.SendMessage(channelID, fmt.Sprintf("Unknown command `%s`.", payload.Arguments["name"]))
+			return
+		}
+		if commandDefinition.CommandID == enablementCommandID {
+			client.SendMessage(channelID, "This command can't be disabled.")
+			return
+		}
+		name, displayName = commandEnablementName(commandDefinition.CommandID), commandDefinition.CommandID
+	}
+
+	scopeChannelID, scopeName := "", "this server"
+
+	switch scope := payload.Arguments["scope"]; {
+	case scope == "here":
+		scopeChannelID, scopeName = channelID, "this channel"
+	case strings.HasPrefix(scope, "<#"):
+		scopeChannelID, scopeName = strings.Trim(scope, "<#>"), scope
+	}
+
+	enabled := payload.Trigger == "enable"
+
+	if err := bot.Enablement.SetEnabled(guildID, scopeChannelID, name, enabled); err != nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unable to update `%s`: %v", displayName, err))
+		return
+	}
+
+	client.SendMessage(channelID, fmt.Sprintf("`%s` %sd for %s.", displayName, payload.Trigger, scopeName))
+}
@@ -0,0 +1,124 @@
+package discordgobot
+
+import (
+	"sync"
+)
+
+// EnablementStore holds whether plugins and commands are enabled in a guild or channel.
+// Names are namespaced by the bot as "plugin:<Name>" and "command:<CommandID>".
+type EnablementStore interface {
+	// GetEnabled returns the enabled state of a name in a guild or channel. isSet is false when no state has been stored.
+	GetEnabled(guildID string, channelID string, name string) (enabled bool, isSet bool, err error)
+	// SetEnabled stores the enabled state of a name in a guild, or a channel when channelID is not empty
+	SetEnabled(guildID string, channelID string, name string, enabled bool) error
+	// ClearEnabled removes the stored state of a name in a guild, or a channel when channelID is not empty
+	ClearEnabled(guildID string, channelID string, name string) error
+}
+
+// MemoryEnablementStore is an EnablementStore that only lives as long as the process
+type MemoryEnablementStore struct {
+	sync.RWMutex
+	scopes map[string]map[string]bool
+}
+
+// NewMemoryEnablementStore creates an empty MemoryEnablementStore
+func NewMemoryEnablementStore() *MemoryEnablementStore {
+	return &MemoryEnablementStore{
+		scopes: make(map[string]map[string]bool),
+	}
+}
+
+// GetEnabled returns the enabled state of a name in a guild or channel. isSet is false when no state has been stored.
+func (s *MemoryEnablementStore) GetEnabled(guildID string, channelID string, name string) (bool, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	enabled, isSet := s.scopes[enablementScope(guildID, channelID)][name]
+
+	return enabled, isSet, nil
+}
+
+// SetEnabled stores the enabled state of a name in a guild, or a channel when channelID is not empty
+func (s *MemoryEnablementStore) SetEnabled(guildID string, channelID string, name string, enabled bool) error {
+	s.Lock()
+	defer s.Unlock()
+
+	scope := enablementScope(guildID, channelID)
+
+	if s.scopes[scope] == nil {
+		s.scopes[scope] = make(map[string]bool)
+	}
+
+	s.scopes[scope][name] = enabled
+
+	return nil
+}
+
+// ClearEnabled removes the stored state of a name in a guild, or a channel when channelID is not empty
+func (s *MemoryEnablementStore) ClearEnabled(guildID string, channelID string, name string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	scope := enablementScope(guildID, channelID)
+
+	delete(s.scopes[scope], name)
+
+	if len(s.scopes[scope]) == 0 {
+		delete(s.scopes, scope)
+	}
+
+	return nil
+}
+
+// FileEnablementStore is an EnablementStore that writes every change to a JSON file
+type FileEnablementStore struct {
+	*MemoryEnablementStore
+	file jsonFileStore
+}
+
+// NewFileEnablementStore creates a FileEnablementStore, loading any state already saved to fileName
+func NewFileEnablementStore(fileName string) (*FileEnablementStore, error) {
+	s := &FileEnablementStore{
+		MemoryEnablementStore: NewMemoryEnablementStore(),
+		file:                  jsonFileStore{fileName: fileName},
+	}
+
+	if err := readJSONFile(fileName, &s.scopes); err != nil {
+		return nil, err
+	}
+
+	if s.scopes == nil {
+		s.scopes = make(map[string]map[string]bool)
+	}
+
+	return s, nil
+}
+
+// SetEnabled stores the enabled state of a name in a guild, or a channel when channelID is not empty
+func (s *FileEnablementStore) SetEnabled(guildID string, channelID string, name string, enabled bool) error {
+	return s.file.update(func() error {
+		return s.MemoryEnablementStore.SetEnabled(guildID, channelID, name, enabled)
+	}, s.save)
+}
+
+// ClearEnabled removes the stored state of a name in a guild, or a channel when channelID is not empty
+func (s *FileEnablementStore) ClearEnabled(guildID string, channelID string, name string) error {
+	return s.file.update(func() error {
+		return s.MemoryEnablementStore.ClearEnabled(guildID, channelID, name)
+	}, s.save)
+}
+
+func (s *FileEnablementStore) save(fileName string) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	return writeJSONFile(fileName, s.scopes)
+}
+
+func enablementScope(guildID string, channelID string) string {
+	if channelID == "" {
+		return guildID
+	}
+
+	return guildID + "/" + channelID
+}
@@ -0,0 +1,194 @@
+package discordgobot
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lampjaw/discordclient"
+)
+
+// Event names used when logging plugin panics
+const (
+	EVENT_MESSAGE         = "message"
+	EVENT_MESSAGE_EDIT    = "message_edit"
+	EVENT_MESSAGE_DELETE  = "message_delete"
+	EVENT_REACTION_ADD    = "reaction_add"
+	EVENT_REACTION_REMOVE = "reaction_remove"
+	EVENT_MEMBER_JOIN     = "member_join"
+	EVENT_MEMBER_LEAVE    = "member_leave"
+	EVENT_VOICE_STATE     = "voice_state"
+)
+
+// IMessageEditPlugin is implemented by plugins that handle edited messages
+type IMessageEditPlugin interface {
+	OnMessageEdit(bot *Gobot, client *DiscordClient, message Message)
+}
+
+// IMessageDeletePlugin is implemented by plugins that handle deleted messages. Only the IDs of a deleted message are available.
+type IMessageDeletePlugin interface {
+	OnMessageDelete(bot *Gobot, client *DiscordClient, message Message)
+}
+
+// IReactionAddPlugin is implemented by plugins that handle reactions added to any message
+type IReactionAddPlugin interface {
+	OnReactionAdd(bot *Gobot, client *DiscordClient, reaction *discordgo.MessageReaction)
+}
+
+// IReactionRemovePlugin is implemented by plugins that handle reactions removed from any message
+type IReactionRemovePlugin interface {
+	OnReactionRemove(bot *Gobot, client *DiscordClient, reaction *discordgo.MessageReaction)
+}
+
+// IMemberJoinPlugin is implemented by plugins that handle members joining a guild
+type IMemberJoinPlugin interface {
+	OnMemberJoin(bot *Gobot, client *DiscordClient, member *discordgo.Member)
+}
+
+// IMemberLeavePlugin is implemented by plugins that handle members leaving a guild
+type IMemberLeavePlugin interface {
+	OnMemberLeave(bot *Gobot, client *DiscordClient, member *discordgo.Member)
+}
+
+// IVoiceStatePlugin is implemented by plugins that handle users joining, leaving or changing state in voice channels
+type IVoiceStatePlugin interface {
+	OnVoiceStateUpdate(bot *Gobot, client *DiscordClient, state *discordgo.VoiceState)
+}
+
+func (b *Gobot) registerEventHandlers() {
+	for _, session := range b.Client.Sessions {
+		session.AddHandler(b.onReactionAddEvent)
+		session.AddHandler(b.onReactionRemoveEvent)
+		session.AddHandler(b.onMemberJoinEvent)
+		session.AddHandler(b.onMemberLeaveEvent)
+		session.AddHandler(b.onVoiceStateEvent)
+	}
+}
+
+// runPluginHandler calls a plugin handler and recovers from any panic it causes. Handlers of plugins that stopped
+// being loaded, e.g. because they're being reloaded, are skipped.
+func (b *Gobot) runPluginHandler(plugin IPlugin, event string, handler func()) {
+	if !b.isPluginLoaded(plugin) {
+		return
+	}
+
+	defer b.recoverPanic("Plugin panicked", Field(LOG_FIELD_PLUGIN, plugin.Name()), Field("event", event))
+
+	handler()
+}
+
+// recoverPanic logs a recovered panic. It must be deferred.
+func (b *Gobot) recoverPanic(message string, fields ...LogField) {
+	if r := recover(); r != nil {
+		b.logPanic(r, message, fields...)
+	}
+}
+
+// recoverSpanPanic logs a recovered panic and marks span as failed. It must be deferred before span is ended.
+func (b *Gobot) recoverSpanPanic(span Span, message string, fields ...LogField) {
+	if r := recover(); r != nil {
+		span.SetError(b.logPanic(r, message, fields...))
+	}
+}
+
+func (b *Gobot) logPanic(r interface{}, message string, fields ...LogField) error {
+	err := fmt.Errorf("%v", r)
+	b.logError(message, err, append(fields, Field("stack", string(debug.Stack())))...)
+
+	return err
+}
+
+// eventPlugins returns the plugins that loaded and are enabled for a guild and channel
+func (b *Gobot) eventPlugins(guildID string, channelID string) []IPlugin {
+	plugins := make([]IPlugin, 0, len(b.Plugins))
+
+	for _, plugin := range b.Plugins {
+		if !b.isPluginLoaded(plugin) || !b.isEnabledIn(pluginEnablementName(plugin.Name()), guildID, channelID) {
+			continue
+		}
+
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins
+}
+
+// dispatchMessageEvent sends edited and deleted messages to plugins implementing IMessageEditPlugin or IMessageDeletePlugin
+func (b *Gobot) dispatchMessageEvent(message Message) {
+	for _, plugin := range b.Plugins {
+		if !b.isPluginLoaded(plugin) || !b.IsPluginEnabled(plugin, message) {
+			continue
+		}
+
+		switch message.Type() {
+		case discordclient.MessageTypeUpdate:
+			if p, ok := plugin.(IMessageEditPlugin); ok {
+				go b.runPluginHandler(plugin, EVENT_MESSAGE_EDIT, func() { p.OnMessageEdit(b, b.Client, message) })
+			}
+		case discordclient.MessageTypeDelete:
+			if p, ok := plugin.(IMessageDeletePlugin); ok {
+				go b.runPluginHandler(plugin, EVENT_MESSAGE_DELETE, func() { p.OnMessageDelete(b, b.Client, message) })
+			}
+		}
+	}
+}
+
+func (b *Gobot) onReactionAddEvent(s *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	if reaction.MessageReaction == nil || reaction.UserID == b.Client.UserID() {
+		return
+	}
+
+	for _, plugin := range b.eventPlugins(reaction.GuildID, reaction.ChannelID) {
+		if p, ok := plugin.(IReactionAddPlugin); ok {
+			go b.runPluginHandler(plugin, EVENT_REACTION_ADD, func() { p.OnReactionAdd(b, b.Client, reaction.MessageReaction) })
+		}
+	}
+}
+
+func (b *Gobot) onReactionRemoveEvent(s *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
+	if reaction.MessageReaction == nil || reaction.UserID == b.Client.UserID() {
+		return
+	}
+
+	for _, plugin := range b.eventPlugins(reaction.GuildID, reaction.ChannelID) {
+		if p, ok := plugin.(IReactionRemovePlugin); ok {
+			go b.runPluginHandler(plugin, EVENT_REACTION_REMOVE, func() { p.OnReactionRemove(b, b.Client, reaction.MessageReaction) })
+		}
+	}
+}
+
+func (b *Gobot) onMemberJoinEvent(s *discordgo.Session, member *discordgo.GuildMemberAdd) {
+	if member.Member == nil {
+		return
+	}
+
+	for _, plugin := range b.eventPlugins(member.GuildID, "") {
+		if p, ok := plugin.(IMemberJoinPlugin); ok {
+			go b.runPluginHandler(plugin, EVENT_MEMBER_JOIN, func() { p.OnMemberJoin(b, b.Client, member.Member) })
+		}
+	}
+}
+
+func (b *Gobot) onMemberLeaveEvent(s *discordgo.Session, member *discordgo.GuildMemberRemove) {
+	if member.Member == nil {
+		return
+	}
+
+	for _, plugin := range b.eventPlugins(member.GuildID, "") {
+		if p, ok := plugin.(IMemberLeavePlugin); ok {
+			go b.runPluginHandler(plugin, EVENT_MEMBER_LEAVE, func() { p.OnMemberLeave(b, b.Client, member.Member) })
+		}
+	}
+}
+
+func (b *Gobot) onVoiceStateEvent(s *discordgo.Session, state *discordgo.VoiceStateUpdate) {
+	if state.VoiceState == nil {
+		return
+	}
+
+	for _, plugin := range b.eventPlugins(state.GuildID, state.ChannelID) {
+		if p, ok := plugin.(IVoiceStatePlugin); ok {
+			go b.runPluginHandler(plugin, EVENT_VOICE_STATE, func() { p.OnVoiceStateUpdate(b, b.Client, state.VoiceState) })
+		}
+	}
+}
@@ -8,16 +8,24 @@ import (
 	"os/signal"
 
 	"github.com/lampjaw/discordgobot"
+	"github.com/lampjaw/discordgobot/adminplugin"
 )
 
 func init() {
 	flag.StringVar(&token, "t", "", "Bot Token")
+	flag.StringVar(&exportCommands, "export-commands", "", "Writes the command reference to stdout as json or markdown and exits")
 	flag.Parse()
 }
 
 var token string
+var exportCommands string
 
 func main() {
+	// NewBot doesn't connect to discord so any token can be used to export commands
+	if exportCommands != "" && token == "" {
+		token = "export"
+	}
+
 	if token == "" {
 		fmt.Println("No token provided. Please run: simplebot -t <bot token>")
 		return
@@ -36,6 +44,7 @@ func main() {
 	}
 
 	b.RegisterPlugin(NewExamplePlugin())
+	b.RegisterPlugin(adminplugin.New())
 
 	b.RegisterCommand("cmd",
 		"this was registered with RegisterCommand!",
@@ -63,6 +72,15 @@ func main() {
 		},
 	})
 
+	switch exportCommands {
+	case "json":
+		b.ExportCommandsJSON(os.Stdout)
+		return
+	case "markdown":
+		b.ExportCommandsMarkdown(os.Stdout)
+		return
+	}
+
 	b.Open()
 
 	c := make(chan os.Signal, 1)
@@ -78,5 +96,5 @@ out:
 		}
 	}
 
-	b.Save()
+	b.Close()
 }
@@ -0,0 +1,189 @@
+package discordgobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DEFAULT_COMMAND_CATEGORY is the category of commands registered on the bot rather than a plugin
+const DEFAULT_COMMAND_CATEGORY = "General"
+
+// CommandReference describes a command for documentation
+type CommandReference struct {
+	CommandID     string              `json:"commandId"`
+	Category      string              `json:"category"`
+	Plugin        string              `json:"plugin,omitempty"`
+	Description   string              `json:"description,omitempty"`
+	Prefix        string              `json:"prefix"`
+	DynamicPrefix bool                `json:"dynamicPrefix,omitempty"`
+	Triggers      []string            `json:"triggers"`
+	Arguments     []ArgumentReference `json:"arguments,omitempty"`
+	Permission    string              `json:"permission"`
+	Exposure      string              `json:"exposure"`
+	Unlisted      bool                `json:"unlisted,omitempty"`
+	SlashCommand  bool                `json:"slashCommand,omitempty"`
+}
+
+// ArgumentReference describes a command argument for documentation
+type ArgumentReference struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Description string `json:"description,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+}
+
+// CommandReferences describes every registered and plugin command sorted by category and trigger
+func (b *Gobot) CommandReferences() []CommandReference {
+	references := []CommandReference{}
+
+	for _, commandDefinition := range b.RegisteredCommands() {
+		references = append(references, b.commandReference(commandDefinition, ""))
+	}
+
+	for _, plugin := range b.Plugins {
+		for _, commandDefinition := range plugin.Commands() {
+			references = append(references, b.commandReference(commandDefinition, plugin.Name()))
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].Category != references[j].Category {
+			return references[i].Category < references[j].Category
+		}
+
+		return strings.Join(references[i].Triggers, " ") < strings.Join(references[j].Triggers, " ")
+	})
+
+	return references
+}
+
+// ExportCommandsJSON writes every command as a JSON document
+func (b *Gobot) ExportCommandsJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(b.CommandReferences())
+}
+
+// ExportCommandsMarkdown writes a Markdown reference of every listed command grouped by category
+func (b *Gobot) ExportCommandsMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Commands\n")
+
+	category := ""
+
+	for _, reference := range b.CommandReferences() {
+		if reference.Unlisted || len(reference.Triggers) == 0 {
+			continue
+		}
+
+		if reference.Category != category {
+			category = reference.Category
+			fmt.Fprintf(&sb, "\n## %s\n", category)
+		}
+
+		usage := []string{reference.Prefix + reference.Triggers[0]}
+		for _, argument := range reference.Arguments {
+			if argument.Optional {
+				usage = append(usage, fmt.Sprintf("[%s]", argument.Name))
+			} else {
+				usage = append(usage, fmt.Sprintf("<%s>", argument.Name))
+			}
+		}
+
+		fmt.Fprintf(&sb, "\n### `%s`\n\n", strings.Join(usage, " "))
+
+		if reference.Description != "" {
+			fmt.Fprintf(&sb, "%s\n\n", reference.Description)
+		}
+
+		if len(reference.Triggers) > 1 {
+			fmt.Fprintf(&sb, "* Aliases: %s\n", markdownCodeList(reference.Prefix, reference.Triggers[1:]))
+		}
+
+		fmt.Fprintf(&sb, "* Permission: %s\n", reference.Permission)
+		fmt.Fprintf(&sb, "* Exposure: %s\n", reference.Exposure)
+
+		if reference.DynamicPrefix {
+			sb.WriteString("* Prefix: dynamic\n")
+		}
+
+		if reference.SlashCommand {
+			fmt.Fprintf(&sb, "* Slash command: `/%s`\n", strings.ToLower(reference.Triggers[0]))
+		}
+
+		for _, argument := range reference.Arguments {
+			description := argument.Description
+			if description == "" {
+				description = fmt.Sprintf("Matches `%s`", argument.Pattern)
+			}
+
+			optional := ""
+			if argument.Optional {
+				optional = " (optional)"
+			}
+
+			fmt.Fprintf(&sb, "* `%s`%s - %s\n", argument.Name, optional, description)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func (b *Gobot) commandReference(commandDefinition *CommandDefinition, pluginName string) CommandReference {
+	category := commandDefinition.Category
+	if category == "" {
+		category = pluginName
+	}
+	if category == "" {
+		category = DEFAULT_COMMAND_CATEGORY
+	}
+
+	prefix := commandDefinition.CommandPrefix
+	if prefix == "" && b.Config != nil {
+		prefix = b.Config.CommandPrefix
+	}
+	if prefix == "" {
+		prefix = DEFAULT_COMMAND_PREFIX
+	}
+
+	reference := CommandReference{
+		CommandID:     commandDefinition.CommandID,
+		Category:      category,
+		Plugin:        pluginName,
+		Description:   commandDefinition.Description,
+		Prefix:        prefix,
+		DynamicPrefix: commandDefinition.CommandPrefixFunc != nil || (commandDefinition.CommandPrefix == "" && b.Config != nil && b.Config.CommandPrefixFunc != nil),
+		Triggers:      commandDefinition.Triggers,
+		Permission:    commandDefinition.PermissionLevel.String(),
+		Exposure:      commandDefinition.ExposureLevel.String(),
+		Unlisted:      commandDefinition.Unlisted,
+		SlashCommand:  commandDefinition.SlashCommand,
+	}
+
+	for _, argument := range commandDefinition.Arguments {
+		reference.Arguments = append(reference.Arguments, ArgumentReference{
+			Name:        argument.Alias,
+			Pattern:     argument.Pattern,
+			Description: argument.Description,
+			Optional:    argument.Optional,
+		})
+	}
+
+	return reference
+}
+
+func markdownCodeList(prefix string, values []string) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = fmt.Sprintf("`%s%s`", prefix, value)
+	}
+
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,88 @@
+package discordgobot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// jsonFileStore keeps the JSON file of an in memory store up to date. Changes are applied and written while holding
+// its lock so concurrent changes are written in the order they were made.
+type jsonFileStore struct {
+	sync.Mutex
+	fileName string
+}
+
+// update applies a change and writes the file when it succeeds
+func (f *jsonFileStore) update(change func() error, write func(fileName string) error) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	return write(f.fileName)
+}
+
+func readJSONFile(fileName string, v interface{}) error {
+	b, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func writeJSONFile(fileName string, v interface{}) error {
+	var b bytes.Buffer
+
+	// HTML characters aren't escaped so stored values are written exactly as they were given
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(fileName, b.Bytes())
+}
+
+func writeFileAtomic(fileName string, data []byte) error {
+	dir := filepath.Dir(fileName)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, filepath.Base(fileName)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), fileName)
+}
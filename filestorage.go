@@ -0,0 +1,215 @@
+package discordgobot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileStorage is a Storage that keeps each namespace in its own JSON file within a directory.
+// JSON values are stored as is so the files stay readable. Other values are stored base64 encoded.
+type FileStorage struct {
+	sync.RWMutex
+	Directory string
+}
+
+// NewFileStorage creates a FileStorage that writes to directory
+func NewFileStorage(directory string) *FileStorage {
+	return &FileStorage{
+		Directory: directory,
+	}
+}
+
+// Get returns the value of a key or nil if it doesn't exist
+func (s *FileStorage) Get(namespace string, key string) ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return decodeFileValue(value)
+}
+
+// Put stores the value of a key
+func (s *FileStorage) Put(namespace string, key string, value []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return err
+	}
+
+	values[key] = encodeFileValue(value)
+
+	return writeJSONFile(s.fileName(namespace), values)
+}
+
+// Delete removes a key
+func (s *FileStorage) Delete(namespace string, key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+
+	delete(values, key)
+
+	return writeJSONFile(s.fileName(namespace), values)
+}
+
+// DeleteAll removes keys from a namespace, writing its file once
+func (s *FileStorage) DeleteAll(namespace string, keys []string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return err
+	}
+
+	deleted := false
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			delete(values, key)
+			deleted = true
+		}
+	}
+
+	if !deleted {
+		return nil
+	}
+
+	return writeJSONFile(s.fileName(namespace), values)
+}
+
+// List returns every key in a namespace
+func (s *FileStorage) List(namespace string) ([]string, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// GetAll returns every key and value in a namespace
+func (s *FileStorage) GetAll(namespace string) (map[string][]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	values, err := s.read(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]byte, len(values))
+	for key, value := range values {
+		if result[key], err = decodeFileValue(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (s *FileStorage) read(namespace string) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+
+	if err := readJSONFile(s.fileName(namespace), &values); err != nil {
+		return nil, err
+	}
+
+	if values == nil {
+		values = make(map[string]json.RawMessage)
+	}
+
+	return values, nil
+}
+
+func (s *FileStorage) fileName(namespace string) string {
+	return filepath.Join(s.Directory, url.PathEscape(namespace)+".json")
+}
+
+// fileValueEncoding wraps values that can't be stored as is
+type fileValueEncoding struct {
+	Base64 *string `json:"$base64"`
+}
+
+// encodeFileValue stores compact JSON as is. Anything else, including JSON that would be reformatted when the file is
+// written or that looks like an encoded value, is base64 encoded so Get returns exactly what was Put.
+func encodeFileValue(value []byte) json.RawMessage {
+	if json.Valid(value) && !isEncodedFileValue(value) {
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, value) == nil && bytes.Equal(compacted.Bytes(), value) {
+			return json.RawMessage(append([]byte(nil), value...))
+		}
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(value)
+	b, _ := json.Marshal(fileValueEncoding{Base64: &encoded})
+
+	return json.RawMessage(b)
+}
+
+func decodeFileValue(value json.RawMessage) ([]byte, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, value); err != nil {
+		return nil, err
+	}
+
+	if !isEncodedFileValue(compacted.Bytes()) {
+		return compacted.Bytes(), nil
+	}
+
+	var encoding fileValueEncoding
+	if err := json.Unmarshal(compacted.Bytes(), &encoding); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(*encoding.Base64)
+}
+
+// isEncodedFileValue determines if a JSON value is an object holding only a base64 encoded value
+func isEncodedFileValue(value []byte) bool {
+	if len(value) == 0 || value[0] != '{' {
+		return false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil || len(fields) != 1 {
+		return false
+	}
+
+	var encoded string
+	raw, ok := fields["$base64"]
+
+	return ok && json.Unmarshal(raw, &encoded) == nil
+}
@@ -5,4 +5,7 @@ go 1.12
 require (
 	github.com/bwmarrin/discordgo v0.20.1
 	github.com/lampjaw/discordclient v0.0.0-20191202231535-bd49e5a87cbd
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/crypto v0.0.0-20181030102418-4d3f4d9ffa16
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,608 @@
+package discordgobot
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lampjaw/discordclient"
+	"golang.org/x/crypto/ed25519"
+)
+
+// DEFAULT_INTERACTIONS_PATH is the path interactions are received on when InteractionsAddress is configured
+const DEFAULT_INTERACTIONS_PATH = "/interactions"
+
+// Headers discord signs interaction requests with
+const (
+	INTERACTION_SIGNATURE_HEADER = "X-Signature-Ed25519"
+	INTERACTION_TIMESTAMP_HEADER = "X-Signature-Timestamp"
+)
+
+// MAX_INTERACTION_AGE is how far the timestamp of an interaction request may be from the current time. Older requests
+// are rejected so a captured request can't be replayed.
+const MAX_INTERACTION_AGE = 5 * time.Minute
+
+const maxInteractionBodySize = 1 << 20
+
+var applicationCommandNameRegex = regexp.MustCompile(`^[-_a-z0-9]{1,32}$`)
+
+// InteractionType is the type of an incoming interaction
+type InteractionType int
+
+const (
+	INTERACTION_PING InteractionType = 1 + iota
+	INTERACTION_APPLICATION_COMMAND
+)
+
+// InteractionResponseType is the type of a response to an interaction
+type InteractionResponseType int
+
+const (
+	INTERACTION_RESPONSE_PONG                                 InteractionResponseType = 1
+	INTERACTION_RESPONSE_CHANNEL_MESSAGE_WITH_SOURCE          InteractionResponseType = 4
+	INTERACTION_RESPONSE_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE InteractionResponseType = 5
+)
+
+// APPLICATION_COMMAND_OPTION_STRING is the option type used for every CommandDefinitionArgument
+const APPLICATION_COMMAND_OPTION_STRING = 3
+
+// ApplicationCommand is the schema of a slash command
+type ApplicationCommand struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Options     []ApplicationCommandOption `json:"options,omitempty"`
+}
+
+// ApplicationCommandOption is an argument of a slash command
+type ApplicationCommandOption struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Interaction is an interaction received from discord
+type Interaction struct {
+	ID            string            `json:"id"`
+	ApplicationID string            `json:"application_id"`
+	Type          InteractionType   `json:"type"`
+	Data          *InteractionData  `json:"data,omitempty"`
+	GuildID       string            `json:"guild_id,omitempty"`
+	ChannelID     string            `json:"channel_id,omitempty"`
+	Member        *discordgo.Member `json:"member,omitempty"`
+	User          *discordgo.User   `json:"user,omitempty"`
+	Token         string            `json:"token"`
+}
+
+// InteractionData holds the slash command that was used
+type InteractionData struct {
+	ID      string              `json:"id"`
+	Name    string              `json:"name"`
+	Options []InteractionOption `json:"options,omitempty"`
+}
+
+// InteractionOption is a slash command argument value
+type InteractionOption struct {
+	Name  string      `json:"name"`
+	Type  int         `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// InteractionResponse is the reply to an interaction request
+type InteractionResponse struct {
+	Type InteractionResponseType  `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+// InteractionResponseData is the message sent in reply to an interaction
+type InteractionResponseData struct {
+	Content string `json:"content"`
+}
+
+// ApplicationCommandFromDefinition creates a slash command schema from a command definition. The first trigger is used as the name.
+// Discord requires required options to come first so optional arguments are moved after them.
+func ApplicationCommandFromDefinition(commandDefinition *CommandDefinition) (ApplicationCommand, error) {
+	command := ApplicationCommand{}
+
+	if len(commandDefinition.Triggers) == 0 {
+		return command, fmt.Errorf("Command '%s' has no triggers", commandDefinition.CommandID)
+	}
+
+	command.Name = strings.ToLower(commandDefinition.Triggers[0])
+	if !applicationCommandNameRegex.MatchString(command.Name) {
+		return command, fmt.Errorf("Trigger '%s' of command '%s' is not a valid slash command name", commandDefinition.Triggers[0], commandDefinition.CommandID)
+	}
+
+	command.Description = truncateDescription(commandDefinition.Description, commandDefinition.CommandID)
+
+	var optional []ApplicationCommandOption
+
+	for _, argument := range commandDefinition.Arguments {
+		name := strings.ToLower(argument.Alias)
+		if !applicationCommandNameRegex.MatchString(name) {
+			return command, fmt.Errorf("Argument '%s' of command '%s' is not a valid option name", argument.Alias, commandDefinition.CommandID)
+		}
+
+		option := ApplicationCommandOption{
+			Type:        APPLICATION_COMMAND_OPTION_STRING,
+			Name:        name,
+			Description: truncateDescription(argument.Description, argument.Alias),
+			Required:    !argument.Optional,
+		}
+
+		if argument.Optional {
+			optional = append(optional, option)
+		} else {
+			command.Options = append(command.Options, option)
+		}
+	}
+
+	command.Options = append(command.Options, optional...)
+
+	return command, nil
+}
+
+// ApplicationCommands returns the slash command schema of every registered and plugin command with SlashCommand set
+func (b *Gobot) ApplicationCommands() []ApplicationCommand {
+	commands := []ApplicationCommand{}
+
+	for _, slashCommand := range b.slashCommands() {
+		command, err := ApplicationCommandFromDefinition(slashCommand.definition)
+		if err != nil {
+			b.logWarn("Skipping slash command", Field(LOG_FIELD_COMMAND, slashCommand.definition.CommandID), Field(LOG_FIELD_ERROR, err))
+			continue
+		}
+
+		commands = append(commands, command)
+	}
+
+	return commands
+}
+
+// RegisterApplicationCommands replaces the slash commands of the application with ApplicationCommands.
+// Commands are registered for a single guild when guildID is set, which takes effect immediately.
+func (b *Gobot) RegisterApplicationCommands(guildID string) error {
+	if b.Config == nil || b.Config.ClientID == "" {
+		return fmt.Errorf("A ClientID is required to register slash commands")
+	}
+
+	url := discordgo.EndpointAPI + "applications/" + b.Config.ClientID + "/commands"
+	if guildID != "" {
+		url = discordgo.EndpointAPI + "applications/" + b.Config.ClientID + "/guilds/" + guildID + "/commands"
+	}
+
+	_, err := b.Client.Session.RequestWithBucketID("PUT", url, b.ApplicationCommands(), url)
+
+	return err
+}
+
+// InteractionHandler returns an http.Handler that verifies and runs interactions sent by discord.
+// Use it to mount the endpoint on an existing server instead of using InteractionsAddress.
+func (b *Gobot) InteractionHandler() http.Handler {
+	return http.HandlerFunc(b.handleInteractionRequest)
+}
+
+// SignInteraction creates the signature discord sends in INTERACTION_SIGNATURE_HEADER. Useful for sending test requests.
+func SignInteraction(privateKey ed25519.PrivateKey, timestamp string, body []byte) string {
+	return hex.EncodeToString(ed25519.Sign(privateKey, append([]byte(timestamp), body...)))
+}
+
+// NewInteractionRequest creates a signed interaction request for exercising an interactions endpoint locally
+func NewInteractionRequest(url string, privateKey ed25519.PrivateKey, interaction Interaction) (*http.Request, error) {
+	body, err := json.Marshal(interaction)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(INTERACTION_TIMESTAMP_HEADER, timestamp)
+	request.Header.Set(INTERACTION_SIGNATURE_HEADER, SignInteraction(privateKey, timestamp, body))
+
+	return request, nil
+}
+
+func (b *Gobot) handleInteractionRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxInteractionBodySize))
+	if err != nil {
+		http.Error(w, "Unable to read request", http.StatusBadRequest)
+		return
+	}
+
+	if !b.verifyInteraction(r.Header, body) {
+		http.Error(w, "Invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "Invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	switch interaction.Type {
+	case INTERACTION_PING:
+		writeInteractionResponse(w, InteractionResponse{Type: INTERACTION_RESPONSE_PONG})
+	case INTERACTION_APPLICATION_COMMAND:
+		if interaction.Data == nil {
+			http.Error(w, "Missing interaction data", http.StatusBadRequest)
+			return
+		}
+
+		commandDefinition, plugin := b.findSlashCommandDefinition(interaction.Data.Name)
+		if commandDefinition == nil {
+			writeInteractionResponse(w, InteractionResponse{
+				Type: INTERACTION_RESPONSE_CHANNEL_MESSAGE_WITH_SOURCE,
+				Data: &InteractionResponseData{Content: "Unknown command."},
+			})
+			return
+		}
+
+		writeInteractionResponse(w, InteractionResponse{Type: INTERACTION_RESPONSE_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE})
+
+		go b.dispatchInteraction(commandDefinition, plugin, &interaction)
+	default:
+		http.Error(w, "Unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+func (b *Gobot) verifyInteraction(header http.Header, body []byte) bool {
+	if b.Config == nil || b.Config.InteractionPublicKey == "" {
+		return false
+	}
+
+	publicKey, err := hex.DecodeString(b.Config.InteractionPublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		b.logError("Invalid InteractionPublicKey", fmt.Errorf("expected %d hex encoded bytes", ed25519.PublicKeySize))
+		return false
+	}
+
+	signature, err := hex.DecodeString(header.Get(INTERACTION_SIGNATURE_HEADER))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+
+	timestamp := header.Get(INTERACTION_TIMESTAMP_HEADER)
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if age := time.Since(time.Unix(seconds, 0)); age > MAX_INTERACTION_AGE || age < -MAX_INTERACTION_AGE {
+		return false
+	}
+
+	message := append([]byte(timestamp), body...)
+
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature)
+}
+
+// dispatchInteraction runs a slash command through the same enablement and access checks, argument parsing and callback as a text command.
+// plugin is the plugin that owns the command or nil for a registered command.
+func (b *Gobot) dispatchInteraction(commandDefinition *CommandDefinition, plugin IPlugin, interaction *Interaction) {
+	message := newInteractionMessage(b.Client, commandDefinition, interaction)
+
+	ctx, span := b.StartSpan(context.Background(), SPAN_MESSAGE, b.messageSpanFields(message)...)
+	defer span.End()
+
+	b.Metrics.messageReceived(b.shardForMessage(message))
+	b.Metrics.commandMatched(commandDefinition.CommandID)
+
+	if plugin != nil && !b.IsPluginEnabled(plugin, message) {
+		b.denyCommand(span, commandDefinition, DENIED_DISABLED)
+		b.editInteractionResponse(interaction, "This command is disabled here.")
+		return
+	}
+
+	commandCtx, commandSpan := b.StartSpan(ctx, SPAN_COMMAND, Field(LOG_FIELD_COMMAND, commandDefinition.CommandID), Field("trigger", interaction.Data.Name))
+	denial := b.dispatchCommand(commandCtx, commandSpan, commandDefinition, message, interaction.Data.Name, message.trigger)
+	commandSpan.End()
+
+	switch denial {
+	case "":
+	case DENIED_ARGUMENTS:
+		b.editInteractionResponse(interaction, fmt.Sprintf("Invalid arguments. %s", commandDefinition.Help(b.Client, "/")))
+	case DENIED_DISABLED:
+		b.editInteractionResponse(interaction, "This command is disabled here.")
+	default:
+		b.editInteractionResponse(interaction, "You can't use this command here.")
+	}
+}
+
+// replyToInteraction edits the deferred response for the first reply and sends a followup message for every other reply
+func (b *Gobot) replyToInteraction(interaction *Interaction, index int, content string) (*discordgo.Message, error) {
+	if index == 0 {
+		return b.editInteractionResponse(interaction, content)
+	}
+
+	url := discordgo.EndpointWebhookToken(interaction.ApplicationID, interaction.Token)
+
+	response, err := b.interactionRequest("POST", url, InteractionResponseData{Content: content})
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalMessage(response)
+}
+
+func (b *Gobot) editInteractionResponse(interaction *Interaction, content string) (*discordgo.Message, error) {
+	url := discordgo.EndpointWebhookToken(interaction.ApplicationID, interaction.Token) + "/messages/@original"
+
+	response, err := b.interactionRequest("PATCH", url, InteractionResponseData{Content: content})
+	if err != nil {
+		b.logError("Error responding to interaction", err, Field(LOG_FIELD_CHANNEL, interaction.ChannelID))
+		return nil, err
+	}
+
+	return unmarshalMessage(response)
+}
+
+// finishInteraction removes the deferred response of a command whose callback didn't reply through Reply
+func (b *Gobot) finishInteraction(payload CommandPayload) {
+	payload.reply.Lock()
+	replied := payload.reply.sent > 0
+	payload.reply.Unlock()
+
+	if replied {
+		return
+	}
+
+	url := discordgo.EndpointWebhookToken(payload.interaction.ApplicationID, payload.interaction.Token) + "/messages/@original"
+
+	if _, err := b.interactionRequest("DELETE", url, nil); err != nil {
+		b.logError("Error removing interaction response", err, Field(LOG_FIELD_COMMAND, payload.CommandID))
+	}
+}
+
+// interactionRequest calls an interaction webhook endpoint
+func (b *Gobot) interactionRequest(method string, url string, data interface{}) ([]byte, error) {
+	if b.Client == nil || b.Client.Session == nil {
+		return nil, fmt.Errorf("The bot is not connected to discord")
+	}
+
+	return b.Client.Session.RequestWithBucketID(method, url, data, discordgo.EndpointWebhookToken("", ""))
+}
+
+// slashCommand is a command with SlashCommand set and the plugin that owns it, if any
+type slashCommand struct {
+	definition *CommandDefinition
+	plugin     IPlugin
+}
+
+func (b *Gobot) slashCommands() []slashCommand {
+	commands := []slashCommand{}
+
+	for _, commandDefinition := range b.RegisteredCommands() {
+		if commandDefinition.SlashCommand {
+			commands = append(commands, slashCommand{definition: commandDefinition})
+		}
+	}
+
+	for _, plugin := range b.Plugins {
+		if !b.isPluginLoaded(plugin) {
+			continue
+		}
+
+		for _, commandDefinition := range plugin.Commands() {
+			if commandDefinition.SlashCommand {
+				commands = append(commands, slashCommand{definition: commandDefinition, plugin: plugin})
+			}
+		}
+	}
+
+	return commands
+}
+
+func (b *Gobot) findSlashCommandDefinition(name string) (*CommandDefinition, IPlugin) {
+	for _, command := range b.slashCommands() {
+		if len(command.definition.Triggers) > 0 && strings.ToLower(command.definition.Triggers[0]) == name {
+			return command.definition, command.plugin
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *Gobot) startInteractionsServer() {
+	if b.Config == nil || b.Config.InteractionsAddress == "" || b.interactionsServer != nil {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(DEFAULT_INTERACTIONS_PATH, b.InteractionHandler())
+
+	b.interactionsServer = &http.Server{
+		Addr:    b.Config.InteractionsAddress,
+		Handler: mux,
+	}
+
+	go func(server *http.Server) {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			b.logError("Error serving interactions", err)
+		}
+	}(b.interactionsServer)
+}
+
+func (b *Gobot) stopInteractionsServer() {
+	if b.interactionsServer != nil {
+		b.interactionsServer.Close()
+		b.interactionsServer = nil
+	}
+}
+
+func writeInteractionResponse(w http.ResponseWriter, response InteractionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func unmarshalMessage(data []byte) (*discordgo.Message, error) {
+	message := &discordgo.Message{}
+	if err := json.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func truncateDescription(description string, fallback string) string {
+	if description == "" {
+		description = fallback
+	}
+
+	if runes := []rune(description); len(runes) > 100 {
+		description = string(runes[:97]) + "..."
+	}
+
+	return description
+}
+
+// interactionMessage presents a slash command as a Message so it can be handled like a text command
+type interactionMessage struct {
+	client      *DiscordClient
+	interaction *Interaction
+	trigger     string
+	content     string
+	options     map[string]string
+	received    time.Time
+}
+
+func newInteractionMessage(client *DiscordClient, commandDefinition *CommandDefinition, interaction *Interaction) *interactionMessage {
+	options := make(map[string]string, len(interaction.Data.Options))
+	for _, option := range interaction.Data.Options {
+		options[option.Name] = fmt.Sprintf("%v", option.Value)
+	}
+
+	trigger := "/" + interaction.Data.Name
+	parts := []string{trigger}
+
+	for _, argument := range commandDefinition.Arguments {
+		if value := options[strings.ToLower(argument.Alias)]; value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	return &interactionMessage{
+		client:      client,
+		interaction: interaction,
+		trigger:     trigger,
+		content:     strings.Join(parts, " "),
+		options:     options,
+		received:    time.Now(),
+	}
+}
+
+// arguments reads the command arguments from the slash command options by name. Each value must match its argument's pattern
+// and missing optional arguments are empty, the same as a text command.
+func (m *interactionMessage) arguments(arguments []CommandDefinitionArgument) (bool, map[string]string) {
+	parsedArgs := make(map[string]string, len(arguments))
+
+	for _, argument := range arguments {
+		value := strings.TrimSpace(m.options[strings.ToLower(argument.Alias)])
+
+		if value == "" {
+			if !argument.Optional {
+				return false, nil
+			}
+		} else if matched, err := regexp.MatchString(fmt.Sprintf("^(?:%s)$", argument.Pattern), value); err != nil || !matched {
+			return false, nil
+		}
+
+		parsedArgs[argument.Alias] = value
+	}
+
+	return true, parsedArgs
+}
+
+func (m *interactionMessage) user() *discordgo.User {
+	if m.interaction.Member != nil && m.interaction.Member.User != nil {
+		return m.interaction.Member.User
+	}
+
+	return m.interaction.User
+}
+
+func (m *interactionMessage) Channel() string {
+	return m.interaction.ChannelID
+}
+
+func (m *interactionMessage) UserName() string {
+	if m.interaction.Member != nil && m.interaction.Member.Nick != "" {
+		return m.interaction.Member.Nick
+	}
+
+	if user := m.user(); user != nil {
+		return user.Username
+	}
+
+	return ""
+}
+
+func (m *interactionMessage) UserID() string {
+	if user := m.user(); user != nil {
+		return user.ID
+	}
+
+	return ""
+}
+
+func (m *interactionMessage) UserAvatar() string {
+	if user := m.user(); user != nil {
+		return discordgo.EndpointUserAvatar(user.ID, user.Avatar)
+	}
+
+	return ""
+}
+
+func (m *interactionMessage) Message() string {
+	return m.content
+}
+
+func (m *interactionMessage) RawMessage() string {
+	return m.content
+}
+
+func (m *interactionMessage) MessageID() string {
+	return m.interaction.ID
+}
+
+func (m *interactionMessage) Type() discordclient.MessageType {
+	return discordclient.MessageTypeCreate
+}
+
+func (m *interactionMessage) Timestamp() (time.Time, error) {
+	return m.received, nil
+}
+
+func (m *interactionMessage) ResolveGuildID() (string, error) {
+	return m.interaction.GuildID, nil
+}
+
+func (m *interactionMessage) ResolveMessageChannel() (*discordgo.Channel, error) {
+	return m.client.Channel(m.interaction.ChannelID)
+}
+
+func (m *interactionMessage) IsMentionTrigger(trigger string) (bool, string) {
+	return false, ""
+}
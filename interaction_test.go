@@ -0,0 +1,87 @@
+package discordgobot
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestHandleInteractionRequest(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot := &Gobot{
+		Config: &GobotConf{InteractionPublicKey: hex.EncodeToString(publicKey)},
+	}
+
+	ping := Interaction{ID: "1", Type: INTERACTION_PING}
+
+	signedAt := func(privateKey ed25519.PrivateKey, at time.Time) *http.Request {
+		body, _ := json.Marshal(ping)
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+
+		request := httptest.NewRequest("POST", DEFAULT_INTERACTIONS_PATH, bytes.NewReader(body))
+		request.Header.Set(INTERACTION_TIMESTAMP_HEADER, timestamp)
+		request.Header.Set(INTERACTION_SIGNATURE_HEADER, SignInteraction(privateKey, timestamp, body))
+
+		return request
+	}
+
+	valid, err := NewInteractionRequest(DEFAULT_INTERACTIONS_PATH, privateKey, ping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := signedAt(privateKey, time.Now())
+	tampered.Body = httptest.NewRequest("POST", DEFAULT_INTERACTIONS_PATH, bytes.NewReader([]byte(`{"id":"2","type":1}`))).Body
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		want    int
+	}{
+		{"valid signature", valid, http.StatusOK},
+		{"signed with another key", signedAt(otherKey, time.Now()), http.StatusUnauthorized},
+		{"body changed after signing", tampered, http.StatusUnauthorized},
+		{"missing signature", httptest.NewRequest("POST", DEFAULT_INTERACTIONS_PATH, bytes.NewReader([]byte(`{"type":1}`))), http.StatusUnauthorized},
+		{"stale timestamp", signedAt(privateKey, time.Now().Add(-MAX_INTERACTION_AGE-time.Minute)), http.StatusUnauthorized},
+		{"future timestamp", signedAt(privateKey, time.Now().Add(MAX_INTERACTION_AGE+time.Minute)), http.StatusUnauthorized},
+		{"get", httptest.NewRequest("GET", DEFAULT_INTERACTIONS_PATH, nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		bot.handleInteractionRequest(recorder, test.request)
+
+		if recorder.Code != test.want {
+			t.Errorf("%s: status %d, want %d", test.name, recorder.Code, test.want)
+			continue
+		}
+
+		if test.want != http.StatusOK {
+			continue
+		}
+
+		var response InteractionResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Errorf("%s: invalid response %q: %v", test.name, recorder.Body.String(), err)
+		} else if response.Type != INTERACTION_RESPONSE_PONG {
+			t.Errorf("%s: response type %d, want %d", test.name, response.Type, INTERACTION_RESPONSE_PONG)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package discordgobot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// IDependentPlugin is implemented by plugins that must be initialized and loaded after other plugins
+type IDependentPlugin interface {
+	// Dependencies returns the names of the plugins this plugin depends on
+	Dependencies() []string
+}
+
+// IInitPlugin is implemented by plugins that need to prepare before the bot connects to discord
+type IInitPlugin interface {
+	// Init is called in dependency order before the bot connects
+	Init(bot *Gobot) error
+}
+
+// IReadyPlugin is implemented by plugins that need to act once every shard is connected
+type IReadyPlugin interface {
+	// Ready is called in dependency order once every shard has received its ready event
+	Ready(bot *Gobot, client *DiscordClient)
+}
+
+// IUnloadPlugin is implemented by plugins that need to release resources when the bot closes
+type IUnloadPlugin interface {
+	// Unload is called in reverse dependency order when the bot is closed
+	Unload(bot *Gobot) error
+}
+
+// sortPlugins orders plugins so every plugin comes after its dependencies
+func sortPlugins(plugins map[string]IPlugin) ([]IPlugin, error) {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(plugins))
+	sorted := make([]IPlugin, 0, len(plugins))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("A plugin dependency cycle was found: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		if dependentPlugin, ok := plugins[name].(IDependentPlugin); ok {
+			for _, dependency := range dependentPlugin.Dependencies() {
+				if plugins[dependency] == nil {
+					return fmt.Errorf("Plugin '%s' depends on '%s' which is not registered", name, dependency)
+				}
+
+				if err := visit(dependency, path); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[name] = visited
+		sorted = append(sorted, plugins[name])
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+func (b *Gobot) initPlugins() error {
+	for _, plugin := range b.pluginOrder {
+		if dependency := b.failedDependency(plugin); dependency != "" {
+			if err := b.failPlugin(plugin, fmt.Errorf("Plugin '%s' depends on failed plugin '%s'", plugin.Name(), dependency)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if initPlugin, ok := plugin.(IInitPlugin); ok {
+			if err := initPlugin.Init(b); err != nil {
+				if err := b.failPlugin(plugin, fmt.Errorf("Error initializing plugin '%s': %v", plugin.Name(), err)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Gobot) loadPlugins() error {
+	for _, plugin := range b.pluginOrder {
+		if b.isPluginFailed(plugin) {
+			continue
+		}
+
+		if dependency := b.failedDependency(plugin); dependency != "" {
+			if err := b.failPlugin(plugin, fmt.Errorf("Plugin '%s' depends on failed plugin '%s'", plugin.Name(), dependency)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b.registerPluginMigrations(plugin)
+
+		if storagePlugin, ok := plugin.(IStoragePlugin); ok {
+			storagePlugin.SetStorage(b.PluginStorage(plugin.Name()))
+		}
+
+		if err := plugin.Load(b.Client); err != nil {
+			if err := b.failPlugin(plugin, fmt.Errorf("Error loading plugin '%s': %v", plugin.Name(), err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b.setPluginStatus(PluginStatus{
+			Name:   plugin.Name(),
+			Loaded: true,
+		})
+	}
+
+	return nil
+}
+
+func (b *Gobot) registerReadyHandlers() {
+	for _, session := range b.Client.Sessions {
+		session.AddHandler(b.onReady)
+	}
+
+	for _, session := range b.Client.Sessions {
+		if session.DataReady {
+			b.shardReady(session.ShardID)
+		}
+	}
+}
+
+func (b *Gobot) onReady(s *discordgo.Session, ready *discordgo.Ready) {
+	b.shardReady(s.ShardID)
+}
+
+func (b *Gobot) shardReady(shardID int) {
+	b.readyMutex.Lock()
+
+	if b.readyShards == nil {
+		b.readyShards = make(map[int]bool)
+	}
+
+	b.readyShards[shardID] = true
+	allReady := len(b.readyShards) >= len(b.Client.Sessions)
+
+	b.readyMutex.Unlock()
+
+	if allReady {
+		b.readyOnce.Do(b.readyPlugins)
+	}
+}
+
+func (b *Gobot) readyPlugins() {
+	for _, plugin := range b.pluginOrder {
+		if b.isPluginFailed(plugin) {
+			continue
+		}
+
+		if readyPlugin, ok := plugin.(IReadyPlugin); ok {
+			readyPlugin.Ready(b, b.Client)
+		}
+	}
+}
+
+// ReloadPlugin unloads a plugin, initializes it again and loads it from storage. A quarantined plugin is restored once
+// Init and Load succeed. Plugins with a failed dependency can't be reloaded. The plugin isn't sent messages, events
+// or jobs while it's reloading.
+func (b *Gobot) ReloadPlugin(name string) error {
+	plugin := b.Plugins[name]
+	if plugin == nil {
+		return fmt.Errorf("No plugin named '%s' is registered", name)
+	}
+
+	status := b.PluginStatus(name)
+
+	b.setPluginStatus(PluginStatus{Name: name})
+	b.stopPluginJobs(name)
+
+	if !status.Failed() {
+		if unloadPlugin, ok := plugin.(IUnloadPlugin); ok {
+			if err := unloadPlugin.Unload(b); err != nil {
+				b.setPluginStatus(status)
+				b.startPluginJobs(name)
+				return fmt.Errorf("Error unloading plugin '%s': %v", name, err)
+			}
+		}
+	}
+
+	if dependency := b.failedDependency(plugin); dependency != "" {
+		return b.failReload(name, fmt.Errorf("Plugin '%s' depends on failed plugin '%s'", name, dependency))
+	}
+
+	if initPlugin, ok := plugin.(IInitPlugin); ok {
+		if err := initPlugin.Init(b); err != nil {
+			return b.failReload(name, fmt.Errorf("Error initializing plugin '%s': %v", name, err))
+		}
+	}
+
+	b.registerPluginMigrations(plugin)
+
+	if storagePlugin, ok := plugin.(IStoragePlugin); ok {
+		storagePlugin.SetStorage(b.PluginStorage(name))
+	}
+
+	if err := plugin.Load(b.Client); err != nil {
+		return b.failReload(name, fmt.Errorf("Error loading plugin '%s': %v", name, err))
+	}
+
+	b.setPluginStatus(PluginStatus{
+		Name:   name,
+		Loaded: true,
+	})
+
+	b.startPluginJobs(name)
+
+	return nil
+}
+
+// failReload quarantines a plugin that failed to reload regardless of the failure policy
+func (b *Gobot) failReload(name string, err error) error {
+	b.setPluginStatus(PluginStatus{
+		Name: name,
+		Err:  err,
+	})
+
+	return err
+}
+
+// Close saves and unloads every plugin in reverse dependency order, disconnects from discord and closes Storage if
+// it implements io.Closer
+func (b *Gobot) Close() error {
+	if b.stopAutoSave != nil {
+		close(b.stopAutoSave)
+		b.stopAutoSave = nil
+	}
+
+	if b.stopFileWatch != nil {
+		close(b.stopFileWatch)
+		b.stopFileWatch = nil
+	}
+
+	if b.stopAuditPrune != nil {
+		close(b.stopAuditPrune)
+		b.stopAuditPrune = nil
+		b.pruneAudit()
+	}
+
+	b.stopMetricsServer()
+	b.stopInteractionsServer()
+	b.stopScheduler()
+
+	saveErr := b.Save()
+
+	for i := len(b.pluginOrder) - 1; i >= 0; i-- {
+		plugin := b.pluginOrder[i]
+
+		if b.isPluginFailed(plugin) {
+			continue
+		}
+
+		if unloadPlugin, ok := plugin.(IUnloadPlugin); ok {
+			if err := unloadPlugin.Unload(b); err != nil {
+				b.logError("Error unloading plugin", err, Field(LOG_FIELD_PLUGIN, plugin.Name()))
+			}
+		}
+	}
+
+	for _, session := range b.Client.Sessions {
+		session.Close()
+	}
+
+	// Storages such as BoltStorage hold a file lock until they're closed
+	if closer, ok := b.Storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			b.logError("Error closing storage", err)
+		}
+	}
+
+	return saveErr
+}
@@ -0,0 +1,151 @@
+package discordgobot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+const (
+	LOG_DEBUG LogLevel = 1 + iota
+	LOG_INFO
+	LOG_WARN
+	LOG_ERROR
+)
+
+// Standard LogField keys used by the bot
+const (
+	LOG_FIELD_GUILD   = "guild"
+	LOG_FIELD_CHANNEL = "channel"
+	LOG_FIELD_USER    = "user"
+	LOG_FIELD_COMMAND = "command"
+	LOG_FIELD_PLUGIN  = "plugin"
+	LOG_FIELD_SHARD   = "shard"
+	LOG_FIELD_CONTENT = "content"
+	LOG_FIELD_ERROR   = "error"
+)
+
+// LogField is a structured key value pair attached to a log entry
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives every log entry written by the bot
+type Logger interface {
+	Log(level LogLevel, message string, fields ...LogField)
+}
+
+// Field creates a LogField
+func Field(key string, value interface{}) LogField {
+	return LogField{
+		Key:   key,
+		Value: value,
+	}
+}
+
+func (l LogLevel) String() string {
+	switch l {
+	case LOG_DEBUG:
+		return "DEBUG"
+	case LOG_INFO:
+		return "INFO"
+	case LOG_WARN:
+		return "WARN"
+	case LOG_ERROR:
+		return "ERROR"
+	}
+
+	return strconv.Itoa(int(l))
+}
+
+// StdLogger is a Logger that writes to the standard log package
+type StdLogger struct {
+	// Level is the minimum level written. Defaults to LOG_INFO.
+	Level LogLevel
+}
+
+// NewStdLogger creates a StdLogger that writes entries at level and above
+func NewStdLogger(level LogLevel) *StdLogger {
+	return &StdLogger{
+		Level: level,
+	}
+}
+
+// Log writes an entry as `LEVEL message key=value ...`
+func (l *StdLogger) Log(level LogLevel, message string, fields ...LogField) {
+	minLevel := l.Level
+	if minLevel <= 0 {
+		minLevel = LOG_INFO
+	}
+
+	if level < minLevel {
+		return
+	}
+
+	parts := []string{level.String(), message}
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s=%v", field.Key, field.Value))
+	}
+
+	log.Println(strings.Join(parts, " "))
+}
+
+// Logger returns the configured Logger or a StdLogger if none is configured
+func (b *Gobot) Logger() Logger {
+	if b.Config != nil && b.Config.Logger != nil {
+		return b.Config.Logger
+	}
+
+	return defaultLogger
+}
+
+var defaultLogger Logger = NewStdLogger(LOG_INFO)
+
+func (b *Gobot) logDebug(message string, fields ...LogField) {
+	b.Logger().Log(LOG_DEBUG, message, fields...)
+}
+
+func (b *Gobot) logInfo(message string, fields ...LogField) {
+	b.Logger().Log(LOG_INFO, message, fields...)
+}
+
+func (b *Gobot) logWarn(message string, fields ...LogField) {
+	b.Logger().Log(LOG_WARN, message, fields...)
+}
+
+func (b *Gobot) logError(message string, err error, fields ...LogField) {
+	b.Logger().Log(LOG_ERROR, message, append(fields, Field(LOG_FIELD_ERROR, err))...)
+}
+
+// messageLogFields returns the guild, channel, user and shard fields of a message
+func (b *Gobot) messageLogFields(message Message) []LogField {
+	fields := []LogField{
+		Field(LOG_FIELD_CHANNEL, message.Channel()),
+		Field(LOG_FIELD_USER, message.UserID()),
+	}
+
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return fields
+	}
+
+	fields = append(fields, Field(LOG_FIELD_GUILD, guildID))
+
+	return append(fields, Field(LOG_FIELD_SHARD, b.shardForGuild(guildID)))
+}
+
+// messageContentLogFields returns the fields of a message including its content unless content logging is disabled
+func (b *Gobot) messageContentLogFields(message Message) []LogField {
+	fields := b.messageLogFields(message)
+
+	if b.Config == nil || !b.Config.MessageContentLoggingDisabled {
+		fields = append(fields, Field(LOG_FIELD_CONTENT, message.RawMessage()))
+	}
+
+	return fields
+}
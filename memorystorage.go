@@ -0,0 +1,94 @@
+package discordgobot
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStorage is a Storage that only lives as long as the process
+type MemoryStorage struct {
+	sync.RWMutex
+	namespaces map[string]map[string][]byte
+}
+
+// NewMemoryStorage creates an empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		namespaces: make(map[string]map[string][]byte),
+	}
+}
+
+// Get returns the value of a key or nil if it doesn't exist
+func (s *MemoryStorage) Get(namespace string, key string) ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	value, ok := s.namespaces[namespace][key]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// Put stores the value of a key
+func (s *MemoryStorage) Put(namespace string, key string, value []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.namespaces[namespace] == nil {
+		s.namespaces[namespace] = make(map[string][]byte)
+	}
+
+	s.namespaces[namespace][key] = append([]byte{}, value...)
+
+	return nil
+}
+
+// Delete removes a key
+func (s *MemoryStorage) Delete(namespace string, key string) error {
+	return s.DeleteAll(namespace, []string{key})
+}
+
+// DeleteAll removes keys from a namespace
+func (s *MemoryStorage) DeleteAll(namespace string, keys []string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, key := range keys {
+		delete(s.namespaces[namespace], key)
+	}
+
+	if len(s.namespaces[namespace]) == 0 {
+		delete(s.namespaces, namespace)
+	}
+
+	return nil
+}
+
+// List returns every key in a namespace
+func (s *MemoryStorage) List(namespace string) ([]string, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	keys := make([]string, 0, len(s.namespaces[namespace]))
+	for key := range s.namespaces[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// GetAll returns every key and value in a namespace
+func (s *MemoryStorage) GetAll(namespace string) (map[string][]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	values := make(map[string][]byte, len(s.namespaces[namespace]))
+	for key, value := range s.namespaces[namespace] {
+		values[key] = append([]byte(nil), value...)
+	}
+
+	return values, nil
+}
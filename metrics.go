@@ -0,0 +1,264 @@
+package discordgobot
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DEFAULT_METRICS_PATH is the path metrics are served on when MetricsAddress is configured
+const DEFAULT_METRICS_PATH = "/metrics"
+
+// metricsLatencyBuckets are the upper bounds in seconds of the callback latency histogram
+var metricsLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics records message and command activity and serves it in the Prometheus text exposition format
+type Metrics struct {
+	sync.Mutex
+
+	messagesReceived map[int]uint64
+	commandsMatched  map[string]uint64
+	commandsExecuted map[string]uint64
+	commandsDenied   map[string]map[CommandDenial]uint64
+	callbackLatency  map[string]*latencyHistogram
+	inFlight         int64
+}
+
+type latencyHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewMetrics creates an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		messagesReceived: make(map[int]uint64),
+		commandsMatched:  make(map[string]uint64),
+		commandsExecuted: make(map[string]uint64),
+		commandsDenied:   make(map[string]map[CommandDenial]uint64),
+		callbackLatency:  make(map[string]*latencyHistogram),
+	}
+}
+
+func (m *Metrics) messageReceived(shardID int) {
+	if m == nil {
+		return
+	}
+
+	m.Lock()
+	m.messagesReceived[shardID]++
+	m.Unlock()
+}
+
+func (m *Metrics) commandMatched(commandID string) {
+	if m == nil {
+		return
+	}
+
+	m.Lock()
+	m.commandsMatched[commandID]++
+	m.Unlock()
+}
+
+func (m *Metrics) commandDenied(commandID string, reason CommandDenial) {
+	if m == nil {
+		return
+	}
+
+	m.Lock()
+	if m.commandsDenied[commandID] == nil {
+		m.commandsDenied[commandID] = make(map[CommandDenial]uint64)
+	}
+	m.commandsDenied[commandID][reason]++
+	m.Unlock()
+}
+
+// commandStarted marks a callback as in flight and returns a function to call when it finishes
+func (m *Metrics) commandStarted(commandID string) func() {
+	if m == nil {
+		return func() {}
+	}
+
+	m.Lock()
+	m.inFlight++
+	m.Unlock()
+
+	start := time.Now()
+
+	return func() {
+		seconds := time.Since(start).Seconds()
+
+		m.Lock()
+		defer m.Unlock()
+
+		m.inFlight--
+		m.commandsExecuted[commandID]++
+
+		histogram := m.callbackLatency[commandID]
+		if histogram == nil {
+			histogram = &latencyHistogram{
+				buckets: make([]uint64, len(metricsLatencyBuckets)),
+			}
+			m.callbackLatency[commandID] = histogram
+		}
+
+		for i, bound := range metricsLatencyBuckets {
+			if seconds <= bound {
+				histogram.buckets[i]++
+			}
+		}
+		histogram.count++
+		histogram.sum += seconds
+	}
+}
+
+// ServeHTTP writes every metric in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var sb strings.Builder
+
+	writeHeader := func(name string, metricType string, help string) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	}
+
+	writeHeader("gobot_messages_received_total", "counter", "Messages received from discord.")
+	shards := make([]int, 0, len(m.messagesReceived))
+	for shardID := range m.messagesReceived {
+		shards = append(shards, shardID)
+	}
+	sort.Ints(shards)
+	for _, shardID := range shards {
+		fmt.Fprintf(&sb, "gobot_messages_received_total{shard=\"%d\"} %d\n", shardID, m.messagesReceived[shardID])
+	}
+
+	writeHeader("gobot_commands_matched_total", "counter", "Messages that matched a command trigger.")
+	for _, commandID := range sortedKeys(m.commandsMatched) {
+		fmt.Fprintf(&sb, "gobot_commands_matched_total{command=%s} %d\n", strconv.Quote(commandID), m.commandsMatched[commandID])
+	}
+
+	writeHeader("gobot_commands_executed_total", "counter", "Command callbacks that finished running.")
+	for _, commandID := range sortedKeys(m.commandsExecuted) {
+		fmt.Fprintf(&sb, "gobot_commands_executed_total{command=%s} %d\n", strconv.Quote(commandID), m.commandsExecuted[commandID])
+	}
+
+	writeHeader("gobot_commands_denied_total", "counter", "Matched commands that were not run.")
+	deniedCommands := make([]string, 0, len(m.commandsDenied))
+	for commandID := range m.commandsDenied {
+		deniedCommands = append(deniedCommands, commandID)
+	}
+	sort.Strings(deniedCommands)
+	for _, commandID := range deniedCommands {
+		reasons := make([]string, 0, len(m.commandsDenied[commandID]))
+		for reason := range m.commandsDenied[commandID] {
+			reasons = append(reasons, string(reason))
+		}
+		sort.Strings(reasons)
+
+		for _, reason := range reasons {
+			fmt.Fprintf(&sb, "gobot_commands_denied_total{command=%s,reason=%s} %d\n", strconv.Quote(commandID), strconv.Quote(reason), m.commandsDenied[commandID][CommandDenial(reason)])
+		}
+	}
+
+	writeHeader("gobot_command_duration_seconds", "histogram", "Command callback latency.")
+	latencyCommands := make([]string, 0, len(m.callbackLatency))
+	for commandID := range m.callbackLatency {
+		latencyCommands = append(latencyCommands, commandID)
+	}
+	sort.Strings(latencyCommands)
+	for _, commandID := range latencyCommands {
+		histogram := m.callbackLatency[commandID]
+		label := strconv.Quote(commandID)
+
+		for i, bound := range metricsLatencyBuckets {
+			fmt.Fprintf(&sb, "gobot_command_duration_seconds_bucket{command=%s,le=\"%s\"} %d\n", label, strconv.FormatFloat(bound, 'g', -1, 64), histogram.buckets[i])
+		}
+		fmt.Fprintf(&sb, "gobot_command_duration_seconds_bucket{command=%s,le=\"+Inf\"} %d\n", label, histogram.count)
+		fmt.Fprintf(&sb, "gobot_command_duration_seconds_sum{command=%s} %s\n", label, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&sb, "gobot_command_duration_seconds_count{command=%s} %d\n", label, histogram.count)
+	}
+
+	writeHeader("gobot_commands_in_flight", "gauge", "Command callbacks currently running.")
+	fmt.Fprintf(&sb, "gobot_commands_in_flight %d\n", m.inFlight)
+
+	writeHeader("gobot_goroutines", "gauge", "Goroutines that currently exist.")
+	fmt.Fprintf(&sb, "gobot_goroutines %d\n", runtime.NumGoroutine())
+
+	n, err := io.WriteString(w, sb.String())
+
+	return int64(n), err
+}
+
+func sortedKeys(values map[string]uint64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (b *Gobot) startMetricsServer() {
+	if b.Metrics == nil || b.Config == nil || b.Config.MetricsAddress == "" || b.metricsServer != nil {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(DEFAULT_METRICS_PATH, b.Metrics)
+
+	b.metricsServer = &http.Server{
+		Addr:    b.Config.MetricsAddress,
+		Handler: mux,
+	}
+
+	go func(server *http.Server) {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			b.logError("Error serving metrics", err)
+		}
+	}(b.metricsServer)
+}
+
+func (b *Gobot) stopMetricsServer() {
+	if b.metricsServer != nil {
+		b.metricsServer.Close()
+		b.metricsServer = nil
+	}
+}
+
+// shardForMessage returns the shard a message was received on based on its guild
+func (b *Gobot) shardForMessage(message Message) int {
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return 0
+	}
+
+	return b.shardForGuild(guildID)
+}
+
+func (b *Gobot) shardForGuild(guildID string) int {
+	if b.Client == nil || b.Client.Session == nil || b.Client.Session.ShardCount < 1 {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int((id >> 22) % uint64(b.Client.Session.ShardCount))
+}
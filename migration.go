@@ -0,0 +1,137 @@
+package discordgobot
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MigrationFunc upgrades plugin state from one schema version to the next
+type MigrationFunc func(data json.RawMessage) (json.RawMessage, error)
+
+// IMigrationPlugin is implemented by plugins whose saved state has changed format over time.
+// Migrations maps a schema version to the function that upgrades state from that version to the next.
+// State saved before any migrations existed is version 0.
+type IMigrationPlugin interface {
+	Migrations() map[int]MigrationFunc
+}
+
+type pluginState struct {
+	SchemaVersion *int            `json:"schemaVersion"`
+	State         json.RawMessage `json:"state"`
+}
+
+// RegisterMigration registers a function that upgrades a plugin's state from version to version+1
+func (b *Gobot) RegisterMigration(pluginName string, version int, migration MigrationFunc) {
+	b.migrationMutex.Lock()
+	defer b.migrationMutex.Unlock()
+
+	if b.migrations == nil {
+		b.migrations = make(map[string]map[int]MigrationFunc)
+	}
+
+	if b.migrations[pluginName] == nil {
+		b.migrations[pluginName] = make(map[int]MigrationFunc)
+	}
+
+	if b.migrations[pluginName][version] != nil {
+		b.logWarn("Migration for that version is already registered", Field(LOG_FIELD_PLUGIN, pluginName), Field("version", version))
+	}
+
+	b.migrations[pluginName][version] = migration
+}
+
+func (b *Gobot) registerPluginMigrations(plugin IPlugin) {
+	migrationPlugin, ok := plugin.(IMigrationPlugin)
+	if !ok {
+		return
+	}
+
+	for version, migration := range migrationPlugin.Migrations() {
+		b.RegisterMigration(plugin.Name(), version, migration)
+	}
+}
+
+func (b *Gobot) getMigrations(pluginName string) map[int]MigrationFunc {
+	b.migrationMutex.RLock()
+	defer b.migrationMutex.RUnlock()
+
+	migrations := make(map[int]MigrationFunc, len(b.migrations[pluginName]))
+	for version, migration := range b.migrations[pluginName] {
+		migrations[version] = migration
+	}
+
+	return migrations
+}
+
+// SchemaVersion returns the version state is saved as, one past the highest registered migration
+func (s *PluginStorage) SchemaVersion() int {
+	version := 0
+
+	for from := range s.migrations {
+		if from+1 > version {
+			version = from + 1
+		}
+	}
+
+	return version
+}
+
+// migrateState runs any migrations needed to bring a stored state blob to the current SchemaVersion.
+// The stored blob is backed up and replaced with the migrated state.
+func (s *PluginStorage) migrateState(key string, blob []byte) (json.RawMessage, error) {
+	version, state := decodePluginState(blob)
+	current := s.SchemaVersion()
+
+	if version > current {
+		return nil, fmt.Errorf("State for '%s' is version %d but only version %d is supported", s.Namespace, version, current)
+	}
+
+	if version == current {
+		return state, nil
+	}
+
+	backupKey := fmt.Sprintf("%s.v%d.backup", key, version)
+	if err := s.Put(backupKey, blob); err != nil {
+		return nil, fmt.Errorf("Error backing up state for '%s': %v", s.Namespace, err)
+	}
+
+	for ; version < current; version++ {
+		migration := s.migrations[version]
+		if migration == nil {
+			return nil, fmt.Errorf("No migration registered for '%s' from version %d", s.Namespace, version)
+		}
+
+		migrated, err := migration(state)
+		if err != nil {
+			return nil, fmt.Errorf("Error migrating state for '%s' from version %d: %v", s.Namespace, version, err)
+		}
+
+		state = migrated
+	}
+
+	if err := s.putState(key, current, state); err != nil {
+		return nil, err
+	}
+
+	s.getLogger().Log(LOG_INFO, "Migrated plugin state", Field(LOG_FIELD_PLUGIN, s.Namespace), Field("version", current), Field("backup", backupKey))
+
+	return state, nil
+}
+
+func (s *PluginStorage) putState(key string, version int, state json.RawMessage) error {
+	return s.SaveJSON(key, pluginState{
+		SchemaVersion: &version,
+		State:         state,
+	})
+}
+
+// decodePluginState reads a versioned state blob. Blobs saved without a version are treated as version 0.
+func decodePluginState(blob []byte) (int, json.RawMessage) {
+	var state pluginState
+
+	if err := json.Unmarshal(blob, &state); err != nil || state.SchemaVersion == nil || state.State == nil {
+		return 0, json.RawMessage(blob)
+	}
+
+	return *state.SchemaVersion, state.State
+}
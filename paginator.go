@@ -0,0 +1,164 @@
+package discordgobot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// PAGINATOR_PREVIOUS is the reaction used to move to the previous page
+	PAGINATOR_PREVIOUS = "◀"
+	// PAGINATOR_NEXT is the reaction used to move to the next page
+	PAGINATOR_NEXT = "▶"
+	// DEFAULT_PAGINATOR_TIMEOUT is how long a paginator responds to reactions if no timeout is configured
+	DEFAULT_PAGINATOR_TIMEOUT = 2 * time.Minute
+)
+
+// Paginator sends a single message that can be paged through with reactions
+type Paginator struct {
+	sync.Mutex
+	// Pages holds the content of each page
+	Pages []string
+	// UserID is the only user allowed to change pages. Any user can change pages when empty.
+	UserID string
+	// Timeout is how long after the last page change the paginator stops responding to reactions.
+	Timeout time.Duration
+
+	bot       *Gobot
+	page      int
+	channelID string
+	messageID string
+	timer     *time.Timer
+}
+
+// NewPaginator creates a Paginator for a set of pages that can only be navigated by userID
+func NewPaginator(bot *Gobot, pages []string, userID string) *Paginator {
+	return &Paginator{
+		Pages:   pages,
+		UserID:  userID,
+		Timeout: DEFAULT_PAGINATOR_TIMEOUT,
+		bot:     bot,
+	}
+}
+
+// PaginateLines groups lines into pages containing at most pageSize lines
+func PaginateLines(lines []string, pageSize int) []string {
+	if pageSize < 1 || len(lines) <= pageSize {
+		return []string{strings.Join(lines, "\n")}
+	}
+
+	pages := make([]string, 0, (len(lines)+pageSize-1)/pageSize)
+
+	for i := 0; i < len(lines); i += pageSize {
+		end := i + pageSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+		pages = append(pages, strings.Join(lines[i:end], "\n"))
+	}
+
+	return pages
+}
+
+// Send posts the first page to a channel and starts listening for navigation reactions
+func (p *Paginator) Send(channelID string) error {
+	if len(p.Pages) == 0 {
+		return fmt.Errorf("Paginator has no pages to send")
+	}
+
+	if len(p.Pages) == 1 {
+		return p.bot.Client.SendMessage(channelID, p.Pages[0])
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	m, err := p.bot.Client.Session.ChannelMessageSend(channelID, p.render())
+	if err != nil {
+		return err
+	}
+
+	p.channelID = channelID
+	p.messageID = m.ID
+
+	p.bot.AddReactionListener(p.messageID, p.onReaction)
+
+	p.bot.Client.Session.MessageReactionAdd(p.channelID, p.messageID, PAGINATOR_PREVIOUS)
+	p.bot.Client.Session.MessageReactionAdd(p.channelID, p.messageID, PAGINATOR_NEXT)
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DEFAULT_PAGINATOR_TIMEOUT
+	}
+	p.timer = time.AfterFunc(timeout, p.Stop)
+
+	return nil
+}
+
+// Stop ends navigation and removes the navigation reactions
+func (p *Paginator) Stop() {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.messageID == "" {
+		return
+	}
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+
+	p.bot.RemoveReactionListener(p.messageID)
+	p.bot.Client.Session.MessageReactionsRemoveAll(p.channelID, p.messageID)
+	p.messageID = ""
+}
+
+func (p *Paginator) onReaction(bot *Gobot, client *DiscordClient, reaction *discordgo.MessageReaction) {
+	if p.UserID != "" && reaction.UserID != p.UserID {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.messageID == "" {
+		return
+	}
+
+	page := p.page
+
+	switch reaction.Emoji.Name {
+	case PAGINATOR_PREVIOUS:
+		page--
+	case PAGINATOR_NEXT:
+		page++
+	default:
+		return
+	}
+
+	client.Session.MessageReactionRemove(p.channelID, p.messageID, reaction.Emoji.Name, reaction.UserID)
+
+	if page < 0 || page >= len(p.Pages) {
+		return
+	}
+
+	p.page = page
+
+	if _, err := client.Session.ChannelMessageEdit(p.channelID, p.messageID, p.render()); err != nil {
+		return
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DEFAULT_PAGINATOR_TIMEOUT
+	}
+	p.timer.Reset(timeout)
+}
+
+func (p *Paginator) render() string {
+	return fmt.Sprintf("%s\n\nPage %d/%d", p.Pages[p.page], p.page+1, len(p.Pages))
+}
@@ -0,0 +1,116 @@
+package discordgobot
+
+import (
+	"strings"
+)
+
+// HasRole checks if the author of a message has a role in the message guild by role ID or name
+func (c *DiscordClient) HasRole(message Message, role string) bool {
+	return hasAnyRole(c, message, []string{role})
+}
+
+// MemberRoleIDs returns the role IDs a user holds in a guild
+func (c *DiscordClient) MemberRoleIDs(guildID string, userID string) ([]string, error) {
+	member, err := c.GuildMember(userID, guildID)
+	if err != nil {
+		member, err = c.Session.GuildMember(guildID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return member.Roles, nil
+}
+
+func hasAnyRole(client *DiscordClient, message Message, roles []string) bool {
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return false
+	}
+
+	memberRoles, err := client.MemberRoleIDs(guildID, message.UserID())
+	if err != nil {
+		return false
+	}
+
+	guild, err := client.Guild(guildID)
+	if err != nil {
+		return false
+	}
+
+	for _, memberRole := range memberRoles {
+		for _, role := range roles {
+			if memberRole == role {
+				return true
+			}
+
+			for _, guildRole := range guild.Roles {
+				if guildRole.ID == memberRole && strings.EqualFold(guildRole.Name, role) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func hasDiscordPermissions(client *DiscordClient, message Message, permissions int) bool {
+	p, err := client.UserChannelPermissions(message.UserID(), message.Channel())
+	if err != nil {
+		return false
+	}
+
+	return p&permissions == permissions
+}
+
+// getPermissionOverride returns the override of a command in the message's guild. Owner commands are never overridden
+// so a server admin can't grant themselves owner access.
+func (b *Gobot) getPermissionOverride(commandDefinition *CommandDefinition, message Message) *PermissionOverride {
+	if b.Permissions == nil || commandDefinition.PermissionLevel == PERMISSION_OWNER {
+		return nil
+	}
+
+	guildID, err := message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		return nil
+	}
+
+	override, err := b.Permissions.GetOverride(guildID, commandDefinition.CommandID)
+	if err != nil {
+		b.logError("Error reading permission override", err, append(b.messageLogFields(message), Field(LOG_FIELD_COMMAND, commandDefinition.CommandID))...)
+		return nil
+	}
+
+	return override
+}
+
+func isPermissionOverrideDenied(client *DiscordClient, override *PermissionOverride, message Message) bool {
+	if containsString(override.DeniedUsers, message.UserID()) || containsString(override.DeniedChannels, message.Channel()) {
+		return true
+	}
+
+	if len(override.AllowedChannels) > 0 && !containsString(override.AllowedChannels, message.Channel()) {
+		return true
+	}
+
+	return len(override.DeniedRoles) > 0 && hasAnyRole(client, message, override.DeniedRoles)
+}
+
+func isPermissionOverrideAllowed(client *DiscordClient, override *PermissionOverride, message Message) bool {
+	if containsString(override.AllowedUsers, message.UserID()) {
+		return true
+	}
+
+	return len(override.AllowedRoles) > 0 && hasAnyRole(client, message, override.AllowedRoles)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
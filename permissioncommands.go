@@ -0,0 +1,199 @@
+package discordgobot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const permissionCommandID = "gobot-cmd-perm"
+
+var (
+	userMentionRegex    = regexp.MustCompile(`^<@!?([0-9]+)>$`)
+	roleMentionRegex    = regexp.MustCompile(`^<@&([0-9]+)>$`)
+	channelMentionRegex = regexp.MustCompile(`^<#([0-9]+)>$`)
+)
+
+var permissionLevelNames = map[string]PermissionLevel{
+	"owner":     PERMISSION_OWNER,
+	"admin":     PERMISSION_ADMIN,
+	"moderator": PERMISSION_MODERATOR,
+	"user":      PERMISSION_USER,
+}
+
+func (b *Gobot) registerPermissionCommands() {
+	b.registerBuiltinCommand(&CommandDefinition{
+		CommandID:   permissionCommandID,
+		Description: "Manages command permissions for this server. Actions: allow, deny, level, reset, show",
+		Triggers: []string{
+			"perm",
+		},
+		Arguments: []CommandDefinitionArgument{
+			{
+				Pattern: "allow|deny|level|reset|show",
+				Alias:   "action",
+			},
+			{
+				Pattern: "\\S+",
+				Alias:   "command",
+			},
+			{
+				Pattern:  ".+",
+				Alias:    "target",
+				Optional: true,
+			},
+		},
+		PermissionLevel: PERMISSION_ADMIN,
+		ExposureLevel:   EXPOSURE_PUBLIC,
+		Callback:        handlePermissionCommand,
+	})
+}
+
+func handlePermissionCommand(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	channelID := payload.Message.Channel()
+
+	if bot.Permissions == nil {
+		client.SendMessage(channelID, "Permission overrides are not available.")
+		return
+	}
+
+	guildID, err := payload.Message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		client.SendMessage(channelID, "Permission overrides can only be managed in a server.")
+		return
+	}
+
+	commandDefinition := bot.FindCommandDefinition(payload.Arguments["command"])
+	if commandDefinition == nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unknown command `%s`.", payload.Arguments["command"]))
+		return
+	}
+
+	commandID := commandDefinition.CommandID
+
+	if commandDefinition.PermissionLevel == PERMISSION_OWNER && payload.Arguments["action"] != "show" {
+		client.SendMessage(channelID, fmt.Sprintf("`%s` is restricted to the bot owner and can't be overridden.", commandID))
+		return
+	}
+
+	override, err := bot.Permissions.GetOverride(guildID, commandID)
+	if err != nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unable to read permissions for `%s`: %v", commandID, err))
+		return
+	}
+
+	if override == nil {
+		override = &PermissionOverride{}
+	}
+
+	target := strings.TrimSpace(payload.Arguments["target"])
+
+	switch payload.Arguments["action"] {
+	case "show":
+		client.SendMessage(channelID, describePermissionOverride(commandID, override))
+		return
+	case "reset":
+		err = bot.Permissions.DeleteOverride(guildID, commandID)
+	case "level":
+		level, ok := permissionLevelNames[strings.ToLower(target)]
+		if !ok {
+			client.SendMessage(channelID, "Level must be one of owner, admin, moderator or user.")
+			return
+		}
+		override.PermissionLevel = level
+		err = bot.Permissions.SetOverride(guildID, commandID, override)
+	case "allow", "deny":
+		if !override.apply(payload.Arguments["action"] == "allow", target) {
+			client.SendMessage(channelID, "Target must be a user, role or channel mention.")
+			return
+		}
+
+		if override.isEmpty() {
+			err = bot.Permissions.DeleteOverride(guildID, commandID)
+		} else {
+			err = bot.Permissions.SetOverride(guildID, commandID, override)
+		}
+	}
+
+	if err != nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unable to update permissions for `%s`: %v", commandID, err))
+		return
+	}
+
+	client.SendMessage(channelID, fmt.Sprintf("Permissions updated for `%s`.", commandID))
+}
+
+func (o *PermissionOverride) apply(allow bool, target string) bool {
+	var allowed, denied *[]string
+	var id string
+
+	if m := roleMentionRegex.FindStringSubmatch(target); m != nil {
+		allowed, denied, id = &o.AllowedRoles, &o.DeniedRoles, m[1]
+	} else if m := userMentionRegex.FindStringSubmatch(target); m != nil {
+		allowed, denied, id = &o.AllowedUsers, &o.DeniedUsers, m[1]
+	} else if m := channelMentionRegex.FindStringSubmatch(target); m != nil {
+		allowed, denied, id = &o.AllowedChannels, &o.DeniedChannels, m[1]
+	} else {
+		return false
+	}
+
+	if !allow {
+		allowed, denied = denied, allowed
+	}
+
+	*denied = removeString(*denied, id)
+
+	if !containsString(*allowed, id) {
+		*allowed = append(*allowed, id)
+	}
+
+	return true
+}
+
+func describePermissionOverride(commandID string, o *PermissionOverride) string {
+	if o.isEmpty() {
+		return fmt.Sprintf("`%s` has no permission overrides.", commandID)
+	}
+
+	lines := []string{fmt.Sprintf("Permission overrides for `%s`:", commandID)}
+
+	for name, level := range permissionLevelNames {
+		if level == o.PermissionLevel {
+			lines = append(lines, fmt.Sprintf("Level: %s", name))
+		}
+	}
+
+	appendMentions := func(label string, format string, ids []string) {
+		if len(ids) == 0 {
+			return
+		}
+
+		mentions := make([]string, len(ids))
+		for i, id := range ids {
+			mentions[i] = fmt.Sprintf(format, id)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %s", label, strings.Join(mentions, ", ")))
+	}
+
+	appendMentions("Allowed users", "<@%s>", o.AllowedUsers)
+	appendMentions("Denied users", "<@%s>", o.DeniedUsers)
+	appendMentions("Allowed roles", "<@&%s>", o.AllowedRoles)
+	appendMentions("Denied roles", "<@&%s>", o.DeniedRoles)
+	appendMentions("Allowed channels", "<#%s>", o.AllowedChannels)
+	appendMentions("Denied channels", "<#%s>", o.DeniedChannels)
+
+	return strings.Join(lines, "\n")
+}
+
+func removeString(values []string, value string) []string {
+	result := values[:0]
+
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
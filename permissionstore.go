@@ -0,0 +1,151 @@
+package discordgobot
+
+import (
+	"sync"
+)
+
+// PermissionOverride changes who can use a command within a guild
+type PermissionOverride struct {
+	// PermissionLevel replaces the command's PermissionLevel when set.
+	PermissionLevel PermissionLevel `json:"permissionLevel,omitempty"`
+	// AllowedUsers are user IDs that can use the command regardless of its permission requirements.
+	AllowedUsers []string `json:"allowedUsers,omitempty"`
+	// DeniedUsers are user IDs that can never use the command.
+	DeniedUsers []string `json:"deniedUsers,omitempty"`
+	// AllowedRoles are role IDs that can use the command regardless of its permission requirements.
+	AllowedRoles []string `json:"allowedRoles,omitempty"`
+	// DeniedRoles are role IDs that can never use the command.
+	DeniedRoles []string `json:"deniedRoles,omitempty"`
+	// AllowedChannels restricts the command to these channel IDs when not empty.
+	AllowedChannels []string `json:"allowedChannels,omitempty"`
+	// DeniedChannels are channel IDs where the command can never be used.
+	DeniedChannels []string `json:"deniedChannels,omitempty"`
+}
+
+// PermissionStore holds permission overrides by guild and CommandID
+type PermissionStore interface {
+	// GetOverride returns the override for a command in a guild or nil if there is none
+	GetOverride(guildID string, commandID string) (*PermissionOverride, error)
+	// SetOverride replaces the override for a command in a guild
+	SetOverride(guildID string, commandID string, override *PermissionOverride) error
+	// DeleteOverride removes the override for a command in a guild
+	DeleteOverride(guildID string, commandID string) error
+}
+
+// MemoryPermissionStore is a PermissionStore that only lives as long as the process
+type MemoryPermissionStore struct {
+	sync.RWMutex
+	overrides map[string]map[string]*PermissionOverride
+}
+
+// NewMemoryPermissionStore creates an empty MemoryPermissionStore
+func NewMemoryPermissionStore() *MemoryPermissionStore {
+	return &MemoryPermissionStore{
+		overrides: make(map[string]map[string]*PermissionOverride),
+	}
+}
+
+// GetOverride returns the override for a command in a guild or nil if there is none
+func (s *MemoryPermissionStore) GetOverride(guildID string, commandID string) (*PermissionOverride, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	override := s.overrides[guildID][commandID]
+	if override == nil {
+		return nil, nil
+	}
+
+	return override.copy(), nil
+}
+
+// SetOverride replaces the override for a command in a guild
+func (s *MemoryPermissionStore) SetOverride(guildID string, commandID string, override *PermissionOverride) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.overrides[guildID] == nil {
+		s.overrides[guildID] = make(map[string]*PermissionOverride)
+	}
+
+	s.overrides[guildID][commandID] = override.copy()
+
+	return nil
+}
+
+// DeleteOverride removes the override for a command in a guild
+func (s *MemoryPermissionStore) DeleteOverride(guildID string, commandID string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.overrides[guildID], commandID)
+
+	if len(s.overrides[guildID]) == 0 {
+		delete(s.overrides, guildID)
+	}
+
+	return nil
+}
+
+// FilePermissionStore is a PermissionStore that writes every change to a JSON file
+type FilePermissionStore struct {
+	*MemoryPermissionStore
+	file jsonFileStore
+}
+
+// NewFilePermissionStore creates a FilePermissionStore, loading any overrides already saved to fileName
+func NewFilePermissionStore(fileName string) (*FilePermissionStore, error) {
+	s := &FilePermissionStore{
+		MemoryPermissionStore: NewMemoryPermissionStore(),
+		file:                  jsonFileStore{fileName: fileName},
+	}
+
+	if err := readJSONFile(fileName, &s.overrides); err != nil {
+		return nil, err
+	}
+
+	if s.overrides == nil {
+		s.overrides = make(map[string]map[string]*PermissionOverride)
+	}
+
+	return s, nil
+}
+
+// SetOverride replaces the override for a command in a guild
+func (s *FilePermissionStore) SetOverride(guildID string, commandID string, override *PermissionOverride) error {
+	return s.file.update(func() error {
+		return s.MemoryPermissionStore.SetOverride(guildID, commandID, override)
+	}, s.save)
+}
+
+// DeleteOverride removes the override for a command in a guild
+func (s *FilePermissionStore) DeleteOverride(guildID string, commandID string) error {
+	return s.file.update(func() error {
+		return s.MemoryPermissionStore.DeleteOverride(guildID, commandID)
+	}, s.save)
+}
+
+func (s *FilePermissionStore) save(fileName string) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	return writeJSONFile(fileName, s.overrides)
+}
+
+func (o *PermissionOverride) copy() *PermissionOverride {
+	return &PermissionOverride{
+		PermissionLevel: o.PermissionLevel,
+		AllowedUsers:    append([]string(nil), o.AllowedUsers...),
+		DeniedUsers:     append([]string(nil), o.DeniedUsers...),
+		AllowedRoles:    append([]string(nil), o.AllowedRoles...),
+		DeniedRoles:     append([]string(nil), o.DeniedRoles...),
+		AllowedChannels: append([]string(nil), o.AllowedChannels...),
+		DeniedChannels:  append([]string(nil), o.DeniedChannels...),
+	}
+}
+
+func (o *PermissionOverride) isEmpty() bool {
+	return o.PermissionLevel == 0 &&
+		len(o.AllowedUsers) == 0 && len(o.DeniedUsers) == 0 &&
+		len(o.AllowedRoles) == 0 && len(o.DeniedRoles) == 0 &&
+		len(o.AllowedChannels) == 0 && len(o.DeniedChannels) == 0
+}
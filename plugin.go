@@ -1,7 +1,6 @@
 package discordgobot
 
 import (
-	"fmt"
 	"sync"
 )
 
@@ -24,6 +23,17 @@ type IPlugin interface {
 // Plugin is the basic model to build bot plugins off of
 type Plugin struct {
 	sync.RWMutex
+	storage *PluginStorage
+}
+
+// SetStorage assigns the plugin's storage. Called by the bot before Load.
+func (p *Plugin) SetStorage(storage *PluginStorage) {
+	p.storage = storage
+}
+
+// Storage returns the plugin's storage. Available from Load onwards.
+func (p *Plugin) Storage() *PluginStorage {
+	return p.storage
 }
 
 // Commands returns an array of CommandDefinitions
@@ -56,7 +66,7 @@ func (p *Plugin) Message(bot *Gobot, client *DiscordClient, message Message) err
 	return nil
 }
 
-func validatePlugin(plugin IPlugin) bool {
+func validatePlugin(logger Logger, plugin IPlugin) bool {
 	errors := make([]string, 0)
 
 	if plugin.Name() == "" {
@@ -71,7 +81,7 @@ func validatePlugin(plugin IPlugin) bool {
 
 	if len(errors) > 0 {
 		for _, errmsg := range errors {
-			fmt.Printf("Plugin validation error: %s: %s", plugin.Name(), errmsg)
+			logger.Log(LOG_ERROR, "Plugin validation error", Field(LOG_FIELD_PLUGIN, plugin.Name()), Field(LOG_FIELD_ERROR, errmsg))
 		}
 		return false
 	}
@@ -0,0 +1,147 @@
+package discordgobot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PluginFailurePolicy determines what happens when a plugin fails to Init or Load
+type PluginFailurePolicy int
+
+const (
+	// PLUGIN_FAILURE_QUARANTINE marks the plugin as failed and stops sending it messages and commands
+	PLUGIN_FAILURE_QUARANTINE PluginFailurePolicy = 1 + iota
+	// PLUGIN_FAILURE_ABORT stops Open and returns the error
+	PLUGIN_FAILURE_ABORT
+)
+
+const pluginStatusCommandID = "gobot-cmd-plugins"
+
+// PluginStatus describes whether a plugin loaded successfully
+type PluginStatus struct {
+	// Name is the name of the plugin
+	Name string
+	// Loaded is true once the plugin has loaded without error
+	Loaded bool
+	// Err holds the error that caused the plugin to fail, if any
+	Err error
+}
+
+// Failed returns true if the plugin failed to initialize or load
+func (s PluginStatus) Failed() bool {
+	return s.Err != nil
+}
+
+// PluginStatus returns the status of a plugin by name
+func (b *Gobot) PluginStatus(name string) PluginStatus {
+	b.pluginStatusMutex.RLock()
+	defer b.pluginStatusMutex.RUnlock()
+
+	if status, ok := b.pluginStatuses[name]; ok {
+		return status
+	}
+
+	return PluginStatus{Name: name}
+}
+
+// PluginStatuses returns the status of every registered plugin sorted by name
+func (b *Gobot) PluginStatuses() []PluginStatus {
+	statuses := make([]PluginStatus, 0, len(b.Plugins))
+
+	for name := range b.Plugins {
+		statuses = append(statuses, b.PluginStatus(name))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+func (b *Gobot) isPluginFailed(plugin IPlugin) bool {
+	return b.PluginStatus(plugin.Name()).Failed()
+}
+
+// isPluginLoaded returns true if a plugin can be sent messages, events and jobs. Plugins that failed or are being
+// reloaded aren't loaded.
+func (b *Gobot) isPluginLoaded(plugin IPlugin) bool {
+	return b.PluginStatus(plugin.Name()).Loaded
+}
+
+func (b *Gobot) setPluginStatus(status PluginStatus) {
+	b.pluginStatusMutex.Lock()
+	defer b.pluginStatusMutex.Unlock()
+
+	if b.pluginStatuses == nil {
+		b.pluginStatuses = make(map[string]PluginStatus)
+	}
+
+	b.pluginStatuses[status.Name] = status
+}
+
+// failPlugin records a plugin failure. An error is returned when the failure policy is to abort.
+func (b *Gobot) failPlugin(plugin IPlugin, err error) error {
+	b.setPluginStatus(PluginStatus{
+		Name: plugin.Name(),
+		Err:  err,
+	})
+
+	if b.Config != nil && b.Config.PluginFailurePolicy == PLUGIN_FAILURE_ABORT {
+		return err
+	}
+
+	b.logWarn("Plugin quarantined", Field(LOG_FIELD_PLUGIN, plugin.Name()), Field(LOG_FIELD_ERROR, err))
+
+	return nil
+}
+
+// failedDependency returns the name of the first dependency of a plugin that has failed
+func (b *Gobot) failedDependency(plugin IPlugin) string {
+	if dependentPlugin, ok := plugin.(IDependentPlugin); ok {
+		for _, dependency := range dependentPlugin.Dependencies() {
+			if b.PluginStatus(dependency).Failed() {
+				return dependency
+			}
+		}
+	}
+
+	return ""
+}
+
+func (b *Gobot) registerPluginStatusCommand() {
+	b.registerBuiltinCommand(&CommandDefinition{
+		CommandID:       pluginStatusCommandID,
+		Description:     "Lists plugins and whether they loaded",
+		Triggers:        []string{"plugins"},
+		PermissionLevel: PERMISSION_OWNER,
+		Callback:        handlePluginStatusCommand,
+	})
+}
+
+func handlePluginStatusCommand(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	client.SendMessage(payload.Message.Channel(), FormatPluginStatuses(bot.PluginStatuses()))
+}
+
+// FormatPluginStatuses creates a human readable list of plugin statuses
+func FormatPluginStatuses(statuses []PluginStatus) string {
+	if len(statuses) == 0 {
+		return "No plugins registered"
+	}
+
+	lines := make([]string, len(statuses))
+
+	for i, status := range statuses {
+		switch {
+		case status.Failed():
+			lines[i] = fmt.Sprintf("`%s` - failed: %v", status.Name, status.Err)
+		case status.Loaded:
+			lines[i] = fmt.Sprintf("`%s` - loaded", status.Name)
+		default:
+			lines[i] = fmt.Sprintf("`%s` - not loaded", status.Name)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,98 @@
+package discordgobot
+
+import (
+	"fmt"
+	"strings"
+)
+
+const prefixCommandID = "gobot-cmd-prefix"
+
+func (b *Gobot) registerPrefixCommands() {
+	b.registerBuiltinCommand(&CommandDefinition{
+		CommandID:   prefixCommandID,
+		Description: "Manages the command prefixes for this server. Actions: show, set, add, remove, reset",
+		Triggers: []string{
+			"prefix",
+		},
+		Arguments: []CommandDefinitionArgument{
+			{
+				Pattern: "show|set|add|remove|reset",
+				Alias:   "action",
+			},
+			{
+				Pattern:  ".+",
+				Alias:    "prefixes",
+				Optional: true,
+			},
+		},
+		PermissionLevel: PERMISSION_ADMIN,
+		ExposureLevel:   EXPOSURE_PUBLIC,
+		Callback:        handlePrefixCommand,
+	})
+}
+
+func handlePrefixCommand(bot *Gobot, client *DiscordClient, payload CommandPayload) {
+	channelID := payload.Message.Channel()
+
+	if bot.Prefixes == nil {
+		client.SendMessage(channelID, "Command prefixes can't be changed.")
+		return
+	}
+
+	guildID, err := payload.Message.ResolveGuildID()
+	if err != nil || guildID == "" {
+		client.SendMessage(channelID, "Command prefixes can only be changed in a server.")
+		return
+	}
+
+	prefixes, err := bot.Prefixes.GetPrefixes(guildID)
+	if err != nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unable to read command prefixes: %v", err))
+		return
+	}
+
+	values := strings.Fields(payload.Arguments["prefixes"])
+	action := payload.Arguments["action"]
+
+	if len(values) == 0 && (action == "set" || action == "add" || action == "remove") {
+		client.SendMessage(channelID, fmt.Sprintf("A prefix is required to %s.", action))
+		return
+	}
+
+	switch action {
+	case "show":
+		client.SendMessage(channelID, fmt.Sprintf("Command prefixes: %s", formatPrefixes(bot.GetCommandPrefixes(payload.Message))))
+		return
+	case "set":
+		prefixes = values
+	case "add":
+		for _, value := range values {
+			if !containsString(prefixes, value) {
+				prefixes = append(prefixes, value)
+			}
+		}
+	case "remove":
+		for _, value := range values {
+			prefixes = removeString(prefixes, value)
+		}
+	case "reset":
+		prefixes = nil
+	}
+
+	if err := bot.Prefixes.SetPrefixes(guildID, prefixes); err != nil {
+		client.SendMessage(channelID, fmt.Sprintf("Unable to update command prefixes: %v", err))
+		return
+	}
+
+	client.SendMessage(channelID, fmt.Sprintf("Command prefixes are now: %s", formatPrefixes(bot.GetCommandPrefixes(payload.Message))))
+}
+
+func formatPrefixes(prefixes []string) string {
+	formatted := make([]string, len(prefixes))
+
+	for i, prefix := range prefixes {
+		formatted[i] = fmt.Sprintf("`%s`", prefix)
+	}
+
+	return strings.Join(formatted, " ")
+}
@@ -0,0 +1,92 @@
+package discordgobot
+
+import (
+	"sync"
+)
+
+// PrefixStore holds command prefixes by guild
+type PrefixStore interface {
+	// GetPrefixes returns the command prefixes for a guild or nil if none are set
+	GetPrefixes(guildID string) ([]string, error)
+	// SetPrefixes replaces the command prefixes for a guild. An empty list removes them.
+	SetPrefixes(guildID string, prefixes []string) error
+}
+
+// MemoryPrefixStore is a PrefixStore that only lives as long as the process
+type MemoryPrefixStore struct {
+	sync.RWMutex
+	prefixes map[string][]string
+}
+
+// NewMemoryPrefixStore creates an empty MemoryPrefixStore
+func NewMemoryPrefixStore() *MemoryPrefixStore {
+	return &MemoryPrefixStore{
+		prefixes: make(map[string][]string),
+	}
+}
+
+// GetPrefixes returns the command prefixes for a guild or nil if none are set
+func (s *MemoryPrefixStore) GetPrefixes(guildID string) ([]string, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	prefixes := s.prefixes[guildID]
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+
+	return append([]string(nil), prefixes...), nil
+}
+
+// SetPrefixes replaces the command prefixes for a guild. An empty list removes them.
+func (s *MemoryPrefixStore) SetPrefixes(guildID string, prefixes []string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(prefixes) == 0 {
+		delete(s.prefixes, guildID)
+		return nil
+	}
+
+	s.prefixes[guildID] = append([]string(nil), prefixes...)
+
+	return nil
+}
+
+// FilePrefixStore is a PrefixStore that writes every change to a JSON file
+type FilePrefixStore struct {
+	*MemoryPrefixStore
+	file jsonFileStore
+}
+
+// NewFilePrefixStore creates a FilePrefixStore, loading any prefixes already saved to fileName
+func NewFilePrefixStore(fileName string) (*FilePrefixStore, error) {
+	s := &FilePrefixStore{
+		MemoryPrefixStore: NewMemoryPrefixStore(),
+		file:              jsonFileStore{fileName: fileName},
+	}
+
+	if err := readJSONFile(fileName, &s.prefixes); err != nil {
+		return nil, err
+	}
+
+	if s.prefixes == nil {
+		s.prefixes = make(map[string][]string)
+	}
+
+	return s, nil
+}
+
+// SetPrefixes replaces the command prefixes for a guild. An empty list removes them.
+func (s *FilePrefixStore) SetPrefixes(guildID string, prefixes []string) error {
+	return s.file.update(func() error {
+		return s.MemoryPrefixStore.SetPrefixes(guildID, prefixes)
+	}, s.save)
+}
+
+func (s *FilePrefixStore) save(fileName string) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	return writeJSONFile(fileName, s.prefixes)
+}
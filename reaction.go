@@ -0,0 +1,55 @@
+package discordgobot
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReactionListener is a callback for reactions added to a watched message
+type ReactionListener func(bot *Gobot, client *DiscordClient, reaction *discordgo.MessageReaction)
+
+// AddReactionListener watches a message for added reactions. Only one listener can be attached to a message.
+func (b *Gobot) AddReactionListener(messageID string, listener ReactionListener) {
+	b.reactionMutex.Lock()
+	defer b.reactionMutex.Unlock()
+
+	if b.reactionListeners == nil {
+		b.reactionListeners = make(map[string]ReactionListener)
+	}
+
+	b.reactionListeners[messageID] = listener
+}
+
+// RemoveReactionListener stops watching a message for added reactions
+func (b *Gobot) RemoveReactionListener(messageID string) {
+	b.reactionMutex.Lock()
+	defer b.reactionMutex.Unlock()
+
+	delete(b.reactionListeners, messageID)
+}
+
+func (b *Gobot) getReactionListener(messageID string) ReactionListener {
+	b.reactionMutex.RLock()
+	defer b.reactionMutex.RUnlock()
+
+	return b.reactionListeners[messageID]
+}
+
+func (b *Gobot) registerReactionHandlers() {
+	for _, session := range b.Client.Sessions {
+		session.AddHandler(b.onMessageReactionAdd)
+	}
+}
+
+func (b *Gobot) onMessageReactionAdd(s *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	if reaction.MessageReaction == nil || reaction.UserID == b.Client.UserID() {
+		return
+	}
+
+	if listener := b.getReactionListener(reaction.MessageID); listener != nil {
+		go func() {
+			defer b.recoverPanic("Reaction listener panicked", Field("message", reaction.MessageID))
+
+			listener(b, b.Client, reaction.MessageReaction)
+		}()
+	}
+}
@@ -0,0 +1,162 @@
+package discordgobot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/lampjaw/discordclient"
+)
+
+// DEFAULT_REPLY_TRACKING_TTL is how long replies to a command are remembered if no ReplyTrackingTTL is configured
+const DEFAULT_REPLY_TRACKING_TTL = 10 * time.Minute
+
+type trackedReplies struct {
+	channelID         string
+	messageIDs        []string
+	expires           time.Time
+	deleteWithMessage bool
+}
+
+type replyTracker struct {
+	sync.Mutex
+	replies map[string]*trackedReplies
+}
+
+// commandReply counts the replies sent by a single run of a command
+type commandReply struct {
+	sync.Mutex
+	sent              int
+	deleteWithMessage bool
+}
+
+// Reply sends a message to the channel of a command and remembers it as a reply to the command's message.
+// With EditRepliesEnabled set, a command run again because its message was edited edits the earlier replies in the order they were sent instead.
+func (b *Gobot) Reply(payload CommandPayload, content string) (*discordgo.Message, error) {
+	channelID := payload.Message.Channel()
+	commandMessageID := payload.Message.MessageID()
+
+	index := 0
+	deleteWithMessage := false
+	if payload.reply != nil {
+		payload.reply.Lock()
+		index = payload.reply.sent
+		payload.reply.sent++
+		deleteWithMessage = payload.reply.deleteWithMessage
+		payload.reply.Unlock()
+	}
+
+	if payload.interaction != nil {
+		return b.replyToInteraction(payload.interaction, index, content)
+	}
+
+	if payload.Edited && b.Config != nil && b.Config.EditRepliesEnabled {
+		if replyID := b.replies.get(commandMessageID, index); replyID != "" {
+			return b.Client.Session.ChannelMessageEdit(channelID, replyID, content)
+		}
+	}
+
+	m, err := b.Client.Session.ChannelMessageSend(channelID, content)
+	if err != nil {
+		return nil, err
+	}
+
+	b.replies.add(commandMessageID, channelID, m.ID, deleteWithMessage, b.replyTrackingTTL())
+
+	return m, nil
+}
+
+// isCommandMessage determines if a message can run commands. Edited messages run commands unless CommandEditsDisabled is set.
+func (b *Gobot) isCommandMessage(message Message) bool {
+	switch message.Type() {
+	case discordclient.MessageTypeCreate:
+		return true
+	case discordclient.MessageTypeUpdate:
+		return b.Config == nil || !b.Config.CommandEditsDisabled
+	}
+
+	return false
+}
+
+func (b *Gobot) replyTrackingTTL() time.Duration {
+	if b.Config != nil && b.Config.ReplyTrackingTTL > 0 {
+		return b.Config.ReplyTrackingTTL
+	}
+
+	return DEFAULT_REPLY_TRACKING_TTL
+}
+
+// deleteReplies deletes the tracked replies of a deleted message if its command has DeleteRepliesWithMessage set
+func (b *Gobot) deleteReplies(message Message) {
+	tracked := b.replies.remove(message.MessageID())
+	if tracked == nil || !tracked.deleteWithMessage {
+		return
+	}
+
+	for _, replyID := range tracked.messageIDs {
+		if err := b.Client.Session.ChannelMessageDelete(tracked.channelID, replyID); err != nil {
+			b.logError("Error deleting reply", err, Field(LOG_FIELD_CHANNEL, tracked.channelID), Field("message", replyID))
+		}
+	}
+}
+
+func (t *replyTracker) add(commandMessageID string, channelID string, replyID string, deleteWithMessage bool, ttl time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+
+	if t.replies == nil {
+		t.replies = make(map[string]*trackedReplies)
+	}
+
+	for id, tracked := range t.replies {
+		if now.After(tracked.expires) {
+			delete(t.replies, id)
+		}
+	}
+
+	tracked := t.replies[commandMessageID]
+	if tracked == nil {
+		tracked = &trackedReplies{
+			channelID: channelID,
+		}
+		t.replies[commandMessageID] = tracked
+	}
+
+	tracked.messageIDs = append(tracked.messageIDs, replyID)
+	tracked.expires = now.Add(ttl)
+	tracked.deleteWithMessage = tracked.deleteWithMessage || deleteWithMessage
+}
+
+// get returns the ID of the reply at index or an empty string if it isn't tracked
+func (t *replyTracker) get(commandMessageID string, index int) string {
+	t.Lock()
+	defer t.Unlock()
+
+	tracked := t.replies[commandMessageID]
+	if tracked == nil || time.Now().After(tracked.expires) || index >= len(tracked.messageIDs) {
+		return ""
+	}
+
+	return tracked.messageIDs[index]
+}
+
+// remove stops tracking the replies to a message and returns them if they haven't expired
+func (t *replyTracker) remove(commandMessageID string) *trackedReplies {
+	t.Lock()
+	defer t.Unlock()
+
+	tracked := t.replies[commandMessageID]
+	if tracked == nil {
+		return nil
+	}
+
+	delete(t.replies, commandMessageID)
+
+	if time.Now().After(tracked.expires) {
+		return nil
+	}
+
+	return tracked
+}
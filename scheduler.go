@@ -0,0 +1,481 @@
+package discordgobot
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SCHEDULER_NAMESPACE is the Storage namespace scheduled jobs are persisted in
+const SCHEDULER_NAMESPACE = "gobot-scheduler"
+
+// MAX_MISSED_RUNS limits how many runs MISSED_RUN_ALL catches up on
+const MAX_MISSED_RUNS = 100
+
+// MissedRunPolicy determines what happens to runs that were due while the bot was offline
+type MissedRunPolicy int
+
+const (
+	// MISSED_RUN_ONCE runs the job once as soon as the bot starts
+	MISSED_RUN_ONCE MissedRunPolicy = 1 + iota
+	// MISSED_RUN_SKIP skips missed runs and waits for the next one. Missed one-shot jobs are removed.
+	MISSED_RUN_SKIP
+	// MISSED_RUN_ALL runs the job once for every missed run, up to MAX_MISSED_RUNS
+	MISSED_RUN_ALL
+)
+
+// JobFunc is called when a scheduled job runs. job.RunAt holds the time the run was scheduled for.
+type JobFunc func(bot *Gobot, job Job)
+
+// Job is a unit of work run by the scheduler on behalf of a plugin
+type Job struct {
+	// ID identifies the job. One is generated if empty. Scheduling a job with the ID of an existing job replaces it.
+	ID string `json:"id"`
+	// Plugin is the name of the plugin that owns the job. Set by Schedule.
+	Plugin string `json:"plugin"`
+	// Handler is the name the JobFunc was registered with through RegisterJobHandler.
+	Handler string `json:"handler"`
+	// RunAt is when the job runs next. For one-shot jobs it is the only run. Interval jobs start at RunAt or after one Interval when zero.
+	RunAt time.Time `json:"runAt"`
+	// Interval repeats the job at a fixed interval.
+	Interval time.Duration `json:"interval,omitempty"`
+	// Cron repeats the job on a five field cron expression in the bot's local time zone.
+	Cron string `json:"cron,omitempty"`
+	// MissedRunPolicy determines how runs missed while the bot was offline are handled. Defaults to MISSED_RUN_ONCE.
+	MissedRunPolicy MissedRunPolicy `json:"missedRunPolicy,omitempty"`
+	// Data holds JSON passed to the handler. Use SetData and DecodeData.
+	Data json.RawMessage `json:"data,omitempty"`
+	// LastRun is when the job last ran
+	LastRun time.Time `json:"lastRun,omitempty"`
+}
+
+// SetData encodes v as the job's data
+func (j *Job) SetData(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	j.Data = b
+
+	return nil
+}
+
+// DecodeData decodes the job's data into v
+func (j Job) DecodeData(v interface{}) error {
+	if len(j.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(j.Data, v)
+}
+
+// IsRecurring returns true for interval and cron jobs
+func (j Job) IsRecurring() bool {
+	return j.Cron != "" || j.Interval > 0
+}
+
+// next returns the first run of a recurring job after t
+func (j Job) next(t time.Time) (time.Time, error) {
+	if j.Cron != "" {
+		schedule, err := ParseCron(j.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		next := schedule.Next(t)
+		if next.IsZero() {
+			return next, fmt.Errorf("Cron expression '%s' never runs", j.Cron)
+		}
+
+		return next, nil
+	}
+
+	if j.Interval <= 0 {
+		return time.Time{}, fmt.Errorf("Job '%s' doesn't recur", j.ID)
+	}
+
+	next := j.RunAt
+	if next.IsZero() {
+		return t.Add(j.Interval), nil
+	}
+
+	if next.After(t) {
+		return next, nil
+	}
+
+	missed := t.Sub(next)/j.Interval + 1
+
+	return next.Add(missed * j.Interval), nil
+}
+
+// runsDue returns how many times a job that is due should run. MISSED_RUN_ALL jobs catch up on every run from RunAt
+// until now, up to MAX_MISSED_RUNS. Every other job runs once.
+func (j Job) runsDue(now time.Time) int {
+	if j.MissedRunPolicy != MISSED_RUN_ALL || !j.IsRecurring() {
+		return 1
+	}
+
+	runs := 0
+	for t := j.RunAt; !t.After(now) && runs < MAX_MISSED_RUNS; runs++ {
+		next, err := j.next(t)
+		if err != nil {
+			break
+		}
+		t = next
+	}
+
+	if runs == 0 {
+		runs = 1
+	}
+
+	return runs
+}
+
+type scheduler struct {
+	sync.Mutex
+	started  bool
+	jobs     map[string]*Job
+	timers   map[string]*time.Timer
+	handlers map[string]JobFunc
+	sequence uint64
+}
+
+// RegisterJobHandler registers the function run by jobs of a plugin that use name as their Handler.
+// Register handlers in Init or Load so persisted jobs can resume when the bot starts.
+func (b *Gobot) RegisterJobHandler(plugin IPlugin, name string, handler JobFunc) {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	if b.scheduler.handlers == nil {
+		b.scheduler.handlers = make(map[string]JobFunc)
+	}
+
+	b.scheduler.handlers[jobHandlerKey(plugin.Name(), name)] = handler
+
+	// Jobs that were due before their handler was registered are started now
+	if b.scheduler.started {
+		b.startJobs(plugin.Name())
+	}
+}
+
+// Schedule persists a job owned by a plugin and starts it once the bot is open. Cron takes priority over Interval and
+// a job without either runs once at RunAt. Rescheduling an existing job with the same schedule keeps its next run time.
+func (b *Gobot) Schedule(plugin IPlugin, job Job) (Job, error) {
+	job.Plugin = plugin.Name()
+
+	if job.Cron != "" {
+		if _, err := ParseCron(job.Cron); err != nil {
+			return job, err
+		}
+	} else if job.Interval < 0 {
+		return job, fmt.Errorf("Job interval must be positive")
+	} else if job.Interval == 0 && job.RunAt.IsZero() {
+		return job, fmt.Errorf("Job requires a RunAt, Interval or Cron")
+	}
+
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	if job.ID == "" {
+		b.scheduler.sequence++
+		job.ID = fmt.Sprintf("%s-%d-%d", job.Plugin, time.Now().UnixNano(), b.scheduler.sequence)
+	}
+
+	existing, err := b.loadJob(job.ID)
+	if err != nil {
+		return job, err
+	}
+
+	if existing != nil && existing.Plugin == job.Plugin && existing.Cron == job.Cron && existing.Interval == job.Interval && existing.IsRecurring() {
+		job.RunAt = existing.RunAt
+		job.LastRun = existing.LastRun
+	} else if job.IsRecurring() {
+		if job.RunAt, err = job.next(time.Now()); err != nil {
+			return job, err
+		}
+	}
+
+	if err := b.saveJob(&job); err != nil {
+		return job, err
+	}
+
+	b.stopJobTimer(job.ID)
+
+	if b.scheduler.started {
+		b.startJob(&job)
+	}
+
+	return job, nil
+}
+
+// CancelJob stops a job and removes it from storage
+func (b *Gobot) CancelJob(id string) error {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	b.stopJobTimer(id)
+
+	return b.Storage.Delete(SCHEDULER_NAMESPACE, id)
+}
+
+// Jobs returns the persisted jobs of a plugin ordered by their next run
+func (b *Gobot) Jobs(pluginName string) ([]Job, error) {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	jobs, err := b.loadJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Job{}
+	for _, job := range jobs {
+		if job.Plugin == pluginName {
+			result = append(result, *job)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RunAt.Before(result[j].RunAt)
+	})
+
+	return result, nil
+}
+
+// startScheduler starts every persisted job whose plugin has loaded
+func (b *Gobot) startScheduler() {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	b.scheduler.started = true
+
+	b.startJobs("")
+}
+
+func (b *Gobot) stopScheduler() {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	b.scheduler.started = false
+
+	for id := range b.scheduler.timers {
+		b.stopJobTimer(id)
+	}
+}
+
+// startPluginJobs resumes the persisted jobs of a plugin after it is reloaded
+func (b *Gobot) startPluginJobs(pluginName string) {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	if b.scheduler.started {
+		b.startJobs(pluginName)
+	}
+}
+
+// stopPluginJobs stops the jobs of a plugin without removing them from storage
+func (b *Gobot) stopPluginJobs(pluginName string) {
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	for id, job := range b.scheduler.jobs {
+		if job.Plugin == pluginName {
+			b.stopJobTimer(id)
+		}
+	}
+}
+
+// startJobs starts the persisted jobs of a plugin or every plugin when pluginName is empty. The scheduler lock must be held.
+func (b *Gobot) startJobs(pluginName string) {
+	jobs, err := b.loadJobs()
+	if err != nil {
+		b.logError("Error loading scheduled jobs", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, job := range jobs {
+		if pluginName != "" && job.Plugin != pluginName {
+			continue
+		}
+
+		if plugin := b.Plugins[job.Plugin]; plugin == nil || !b.isPluginLoaded(plugin) {
+			continue
+		}
+
+		if b.scheduler.jobs[job.ID] != nil {
+			continue
+		}
+
+		if job.RunAt.Before(now) && job.MissedRunPolicy == MISSED_RUN_SKIP {
+			if !job.IsRecurring() {
+				b.Storage.Delete(SCHEDULER_NAMESPACE, job.ID)
+				continue
+			}
+
+			if job.RunAt, err = job.next(now); err != nil {
+				b.logError("Error scheduling job", err, Field(LOG_FIELD_PLUGIN, job.Plugin), Field("job", job.ID))
+				continue
+			}
+			b.saveJob(job)
+		}
+
+		b.startJob(job)
+	}
+}
+
+// startJob sets a timer for the next run of a job. The scheduler lock must be held.
+func (b *Gobot) startJob(job *Job) {
+	if b.scheduler.jobs == nil {
+		b.scheduler.jobs = make(map[string]*Job)
+		b.scheduler.timers = make(map[string]*time.Timer)
+	}
+
+	id := job.ID
+
+	b.scheduler.jobs[id] = job
+	b.scheduler.timers[id] = time.AfterFunc(time.Until(job.RunAt), func() {
+		b.runJob(id)
+	})
+}
+
+// stopJobTimer stops a job from running. The scheduler lock must be held.
+func (b *Gobot) stopJobTimer(id string) {
+	if timer := b.scheduler.timers[id]; timer != nil {
+		timer.Stop()
+	}
+
+	delete(b.scheduler.timers, id)
+	delete(b.scheduler.jobs, id)
+}
+
+func (b *Gobot) runJob(id string) {
+	b.scheduler.Lock()
+	job := b.scheduler.jobs[id]
+	if job == nil {
+		b.scheduler.Unlock()
+		return
+	}
+	run := *job
+	handler := b.scheduler.handlers[jobHandlerKey(run.Plugin, run.Handler)]
+	b.scheduler.Unlock()
+
+	if handler == nil {
+		// The job stays persisted and is started again once its handler is registered or the plugin is reloaded
+		b.logWarn("No handler registered for scheduled job", Field(LOG_FIELD_PLUGIN, run.Plugin), Field("job", run.ID), Field("handler", run.Handler))
+
+		b.scheduler.Lock()
+		if b.scheduler.jobs[id] == job {
+			delete(b.scheduler.jobs, id)
+			delete(b.scheduler.timers, id)
+		}
+		b.scheduler.Unlock()
+
+		return
+	}
+
+	now := time.Now()
+	runs := run.runsDue(now)
+
+	for i := 0; i < runs; i++ {
+		b.callJobHandler(handler, run)
+
+		if i+1 < runs {
+			if next, err := run.next(run.RunAt); err == nil {
+				run.RunAt = next
+			}
+		}
+	}
+
+	b.scheduler.Lock()
+	defer b.scheduler.Unlock()
+
+	if b.scheduler.jobs[id] != job {
+		// The job was cancelled or replaced while running
+		return
+	}
+
+	delete(b.scheduler.jobs, id)
+	delete(b.scheduler.timers, id)
+
+	if !run.IsRecurring() {
+		b.Storage.Delete(SCHEDULER_NAMESPACE, id)
+		return
+	}
+
+	run.LastRun = now
+
+	next, err := run.next(time.Now())
+	if err != nil {
+		b.logError("Error scheduling job", err, Field(LOG_FIELD_PLUGIN, run.Plugin), Field("job", id))
+		return
+	}
+	run.RunAt = next
+
+	if err := b.saveJob(&run); err != nil {
+		b.logError("Error saving scheduled job", err, Field(LOG_FIELD_PLUGIN, run.Plugin), Field("job", id))
+	}
+
+	if b.scheduler.started {
+		b.startJob(&run)
+	}
+}
+
+func (b *Gobot) callJobHandler(handler JobFunc, job Job) {
+	defer b.recoverPanic("Scheduled job panicked", Field(LOG_FIELD_PLUGIN, job.Plugin), Field("job", job.ID))
+
+	handler(b, job)
+}
+
+func (b *Gobot) saveJob(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return b.Storage.Put(SCHEDULER_NAMESPACE, job.ID, data)
+}
+
+func (b *Gobot) loadJob(id string) (*Job, error) {
+	data, err := b.Storage.Get(SCHEDULER_NAMESPACE, id)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	job := &Job{}
+	if err := json.Unmarshal(data, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (b *Gobot) loadJobs() ([]*Job, error) {
+	ids, err := b.Storage.List(SCHEDULER_NAMESPACE)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(ids))
+
+	for _, id := range ids {
+		job, err := b.loadJob(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+func jobHandlerKey(pluginName string, handler string) string {
+	return pluginName + "/" + handler
+}
@@ -0,0 +1,130 @@
+package discordgobot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobNext(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		job     Job
+		from    time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{"interval without RunAt starts after one interval", Job{Interval: time.Hour}, base, base.Add(time.Hour), false},
+		{"interval keeps a future RunAt", Job{Interval: time.Hour, RunAt: base.Add(90 * time.Minute)}, base, base.Add(90 * time.Minute), false},
+		{"interval skips to the next run after missed runs", Job{Interval: time.Hour, RunAt: base}, base.Add(150 * time.Minute), base.Add(3 * time.Hour), false},
+		{"interval due now runs next interval", Job{Interval: time.Hour, RunAt: base}, base, base.Add(time.Hour), false},
+		{"interval keeps its phase", Job{Interval: 15 * time.Minute, RunAt: base.Add(5 * time.Minute)}, base.Add(time.Hour), base.Add(65 * time.Minute), false},
+		{"cron", Job{Cron: "0 * * * *", RunAt: base}, base.Add(15 * time.Minute), base.Add(time.Hour), false},
+		{"cron takes priority over interval", Job{Cron: "30 * * * *", Interval: time.Minute}, base, base.Add(30 * time.Minute), false},
+		{"invalid cron", Job{Cron: "* * *"}, base, time.Time{}, true},
+		{"cron that never runs", Job{Cron: "0 0 30 2 *"}, base, time.Time{}, true},
+		{"one-shot", Job{RunAt: base}, base, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := test.job.next(test.from)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: returned %v", test.name, err)
+			continue
+		}
+
+		if !got.Equal(test.want) {
+			t.Errorf("%s: next(%v) = %v, want %v", test.name, test.from, got, test.want)
+		}
+	}
+}
+
+func TestJobRunsDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		job  Job
+		want int
+	}{
+		{"run once by default", Job{Interval: time.Hour, RunAt: now.Add(-3 * time.Hour)}, 1},
+		{"skip runs once", Job{Interval: time.Hour, RunAt: now.Add(-3 * time.Hour), MissedRunPolicy: MISSED_RUN_SKIP}, 1},
+		{"all runs every missed interval", Job{Interval: time.Hour, RunAt: now.Add(-150 * time.Minute), MissedRunPolicy: MISSED_RUN_ALL}, 3},
+		{"all runs once when on time", Job{Interval: time.Hour, RunAt: now, MissedRunPolicy: MISSED_RUN_ALL}, 1},
+		{"all runs every missed cron run", Job{Cron: "0 * * * *", RunAt: now.Add(-150 * time.Minute), MissedRunPolicy: MISSED_RUN_ALL}, 3},
+		{"all is limited", Job{Interval: time.Minute, RunAt: now.Add(-1000 * time.Hour), MissedRunPolicy: MISSED_RUN_ALL}, MAX_MISSED_RUNS},
+		{"all runs a one-shot job once", Job{RunAt: now.Add(-3 * time.Hour), MissedRunPolicy: MISSED_RUN_ALL}, 1},
+	}
+
+	for _, test := range tests {
+		if got := test.job.runsDue(now); got != test.want {
+			t.Errorf("%s: runsDue = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+type schedulerTestPlugin struct {
+	Plugin
+}
+
+func (p *schedulerTestPlugin) Name() string {
+	return "scheduler-test"
+}
+
+func TestRunJobWithoutHandler(t *testing.T) {
+	plugin := &schedulerTestPlugin{}
+
+	bot := &Gobot{
+		Storage: NewMemoryStorage(),
+		Plugins: map[string]IPlugin{plugin.Name(): plugin},
+	}
+	bot.setPluginStatus(PluginStatus{Name: plugin.Name(), Loaded: true})
+	bot.startScheduler()
+	defer bot.stopScheduler()
+
+	if _, err := bot.Schedule(plugin, Job{ID: "once", Handler: "run", RunAt: time.Now().Add(10 * time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if jobs, err := bot.Jobs(plugin.Name()); err != nil || len(jobs) != 1 {
+		t.Fatalf("Jobs = %v, %v, want the job to stay persisted", jobs, err)
+	}
+
+	ran := make(chan Job, 1)
+	bot.RegisterJobHandler(plugin, "run", func(bot *Gobot, job Job) {
+		ran <- job
+	})
+
+	select {
+	case job := <-ran:
+		if job.ID != "once" {
+			t.Errorf("ran job %q, want %q", job.ID, "once")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("job didn't run once its handler was registered")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		jobs, err := bot.Jobs(plugin.Name())
+		if err == nil && len(jobs) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("Jobs = %v, %v, want the one-shot job removed after it ran", jobs, err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,88 @@
+package discordgobot
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IServicePlugin is implemented by plugins that depend on services registered on the bot.
+// Services returns pointers to the fields to fill, e.g. []interface{}{&p.DB, &p.HTTPClient}.
+// Each field is filled with the registered service of the same type, or the only service assignable to it.
+type IServicePlugin interface {
+	Services() []interface{}
+}
+
+// RegisterService registers a shared service such as a database handle, http client or configuration.
+// Services are found by their type so only one service of each type can be registered.
+func (b *Gobot) RegisterService(service interface{}) error {
+	if service == nil {
+		return fmt.Errorf("Cannot register a nil service")
+	}
+
+	b.serviceMutex.Lock()
+	defer b.serviceMutex.Unlock()
+
+	serviceType := reflect.TypeOf(service)
+
+	for _, registered := range b.services {
+		if reflect.TypeOf(registered) == serviceType {
+			return fmt.Errorf("A service of type %s is already registered", serviceType)
+		}
+	}
+
+	b.services = append(b.services, service)
+
+	return nil
+}
+
+// ResolveService fills target, a pointer, with the registered service of the pointed to type.
+// An interface type is filled with the only registered service that implements it.
+func (b *Gobot) ResolveService(target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return fmt.Errorf("Service target must be a non nil pointer, got %T", target)
+	}
+
+	serviceType := targetValue.Elem().Type()
+
+	b.serviceMutex.RLock()
+	defer b.serviceMutex.RUnlock()
+
+	var matches []interface{}
+
+	for _, service := range b.services {
+		if reflect.TypeOf(service) == serviceType {
+			targetValue.Elem().Set(reflect.ValueOf(service))
+			return nil
+		}
+
+		if reflect.TypeOf(service).AssignableTo(serviceType) {
+			matches = append(matches, service)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("No service of type %s is registered", serviceType)
+	case 1:
+		targetValue.Elem().Set(reflect.ValueOf(matches[0]))
+		return nil
+	}
+
+	return fmt.Errorf("%d registered services are assignable to %s", len(matches), serviceType)
+}
+
+func (b *Gobot) resolvePluginServices(plugin IPlugin) error {
+	servicePlugin, ok := plugin.(IServicePlugin)
+	if !ok {
+		return nil
+	}
+
+	for _, target := range servicePlugin.Services() {
+		if err := b.ResolveService(target); err != nil {
+			return fmt.Errorf("Plugin '%s' is missing a dependency: %v", plugin.Name(), err)
+		}
+	}
+
+	return nil
+}
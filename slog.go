@@ -0,0 +1,48 @@
+//go:build go1.21
+// +build go1.21
+
+package discordgobot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger is a Logger that writes to a log/slog Logger
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger that writes to a log/slog Logger. slog.Default() is used when logger is nil.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogLogger{
+		Logger: logger,
+	}
+}
+
+// Log writes an entry with its fields as slog attributes
+func (l *SlogLogger) Log(level LogLevel, message string, fields ...LogField) {
+	attrs := make([]slog.Attr, len(fields))
+	for i, field := range fields {
+		attrs[i] = slog.Any(field.Key, field.Value)
+	}
+
+	l.Logger.LogAttrs(context.Background(), slogLevel(level), message, attrs...)
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LOG_DEBUG:
+		return slog.LevelDebug
+	case LOG_WARN:
+		return slog.LevelWarn
+	case LOG_ERROR:
+		return slog.LevelError
+	}
+
+	return slog.LevelInfo
+}
@@ -0,0 +1,192 @@
+package discordgobot
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PLUGIN_STATE_KEY is the key used by PluginStorage.LoadState and PluginStorage.SaveState
+const PLUGIN_STATE_KEY = "state"
+
+// RESERVED_NAMESPACE_PREFIX prefixes the Storage namespaces used by the bot itself. Plugins can't use them.
+const RESERVED_NAMESPACE_PREFIX = "gobot-"
+
+// escapedNamespacePrefix is prefixed to plugin namespaces that would otherwise be reserved
+const escapedNamespacePrefix = "_"
+
+// Storage persists values by namespace and key. Plugins are given their own namespace.
+type Storage interface {
+	// Get returns the value of a key or nil if it doesn't exist
+	Get(namespace string, key string) ([]byte, error)
+	// Put stores the value of a key
+	Put(namespace string, key string, value []byte) error
+	// Delete removes a key
+	Delete(namespace string, key string) error
+	// List returns every key in a namespace
+	List(namespace string) ([]string, error)
+}
+
+// NamespaceReader is implemented by storages that can read a whole namespace at once
+type NamespaceReader interface {
+	// GetAll returns every key and value in a namespace
+	GetAll(namespace string) (map[string][]byte, error)
+}
+
+// NamespaceDeleter is implemented by storages that can delete several keys of a namespace at once
+type NamespaceDeleter interface {
+	// DeleteAll removes keys from a namespace
+	DeleteAll(namespace string, keys []string) error
+}
+
+// deleteKeys removes keys from a namespace, using NamespaceDeleter when the storage supports it
+func deleteKeys(storage Storage, namespace string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if deleter, ok := storage.(NamespaceDeleter); ok {
+		return deleter.DeleteAll(namespace, keys)
+	}
+
+	for _, key := range keys {
+		if err := storage.Delete(namespace, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readNamespace returns every key and value in a namespace, using NamespaceReader when the storage supports it
+func readNamespace(storage Storage, namespace string) (map[string][]byte, error) {
+	if reader, ok := storage.(NamespaceReader); ok {
+		return reader.GetAll(namespace)
+	}
+
+	keys, err := storage.List(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(keys))
+
+	for _, key := range keys {
+		value, err := storage.Get(namespace, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// IStoragePlugin is implemented by plugins that can receive a PluginStorage. The bot assigns it before Load is called.
+type IStoragePlugin interface {
+	SetStorage(storage *PluginStorage)
+}
+
+// PluginStorage is a Storage bound to a single namespace
+type PluginStorage struct {
+	Storage   Storage
+	Namespace string
+
+	migrations map[int]MigrationFunc
+	logger     Logger
+}
+
+// Get returns the value of a key or nil if it doesn't exist
+func (s *PluginStorage) Get(key string) ([]byte, error) {
+	return s.Storage.Get(s.Namespace, key)
+}
+
+// Put stores the value of a key
+func (s *PluginStorage) Put(key string, value []byte) error {
+	return s.Storage.Put(s.Namespace, key, value)
+}
+
+// Delete removes a key
+func (s *PluginStorage) Delete(key string) error {
+	return s.Storage.Delete(s.Namespace, key)
+}
+
+// List returns every key in the namespace
+func (s *PluginStorage) List() ([]string, error) {
+	return s.Storage.List(s.Namespace)
+}
+
+func (s *PluginStorage) getLogger() Logger {
+	if s.logger == nil {
+		return defaultLogger
+	}
+
+	return s.logger
+}
+
+// LoadJSON unmarshals the value of a key into v. v is left untouched if the key doesn't exist.
+func (s *PluginStorage) LoadJSON(key string, v interface{}) error {
+	b, err := s.Get(key)
+	if err != nil || b == nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// SaveJSON marshals v and stores it as the value of a key
+func (s *PluginStorage) SaveJSON(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(key, b)
+}
+
+// LoadState unmarshals the plugin's saved state into v, running any registered migrations first.
+// v is left untouched if nothing has been saved.
+func (s *PluginStorage) LoadState(v interface{}) error {
+	b, err := s.Get(PLUGIN_STATE_KEY)
+	if err != nil || b == nil {
+		return err
+	}
+
+	state, err := s.migrateState(PLUGIN_STATE_KEY, b)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(state, v)
+}
+
+// SaveState marshals v and stores it as the plugin's state at the current SchemaVersion
+func (s *PluginStorage) SaveState(v interface{}) error {
+	state, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.putState(PLUGIN_STATE_KEY, s.SchemaVersion(), state)
+}
+
+// PluginStorage returns the storage namespace of a plugin. The namespace is the plugin name, prefixed with an
+// underscore when it starts with RESERVED_NAMESPACE_PREFIX or an underscore so plugins can't use the bot's namespaces.
+func (b *Gobot) PluginStorage(pluginName string) *PluginStorage {
+	return &PluginStorage{
+		Storage:    b.Storage,
+		Namespace:  pluginNamespace(pluginName),
+		migrations: b.getMigrations(pluginName),
+		logger:     b.Logger(),
+	}
+}
+
+func pluginNamespace(pluginName string) string {
+	if strings.HasPrefix(pluginName, RESERVED_NAMESPACE_PREFIX) || strings.HasPrefix(pluginName, escapedNamespacePrefix) {
+		return escapedNamespacePrefix + pluginName
+	}
+
+	return pluginName
+}
@@ -0,0 +1,68 @@
+package discordgobot
+
+import (
+	"context"
+)
+
+// Span names used by the bot while dispatching a message
+const (
+	SPAN_MESSAGE   = "gobot.message"
+	SPAN_PREFIX    = "gobot.prefix"
+	SPAN_MATCH     = "gobot.match"
+	SPAN_COMMAND   = "gobot.command"
+	SPAN_ACCESS    = "gobot.access"
+	SPAN_ARGUMENTS = "gobot.arguments"
+	SPAN_CONFIRM   = "gobot.confirm"
+	SPAN_CALLBACK  = "gobot.callback"
+)
+
+// Tracer creates spans around each step of message dispatch. Implement it to bridge to a tracing library such as OpenTelemetry.
+type Tracer interface {
+	// StartSpan starts a span as a child of any span in ctx and returns a context holding the new span
+	StartSpan(ctx context.Context, name string, fields ...LogField) (context.Context, Span)
+}
+
+// Span is a single timed operation within a trace
+type Span interface {
+	// SetField attaches a key value pair to the span
+	SetField(key string, value interface{})
+	// SetError marks the span as failed
+	SetError(err error)
+	// End finishes the span
+	End()
+}
+
+type noopTracer struct{}
+
+type noopSpan struct{}
+
+func (noopTracer) StartSpan(ctx context.Context, name string, fields ...LogField) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopSpan) SetField(key string, value interface{}) {}
+
+func (noopSpan) SetError(err error) {}
+
+func (noopSpan) End() {}
+
+func (b *Gobot) messageSpanFields(message Message) []LogField {
+	if b.Config == nil || b.Config.Tracer == nil {
+		return nil
+	}
+
+	return b.messageLogFields(message)
+}
+
+// StartSpan starts a span with the configured Tracer. Callbacks can pass CommandPayload.Context to create child spans.
+func (b *Gobot) StartSpan(ctx context.Context, name string, fields ...LogField) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if b.Config == nil || b.Config.Tracer == nil {
+		return noopTracer{}.StartSpan(ctx, name, fields...)
+	}
+
+	return b.Config.Tracer.StartSpan(ctx, name, fields...)
+}